@@ -19,8 +19,10 @@ package apis
 
 import (
 	custompropertyv1beta1 "github.com/rossigee/provider-plausible/apis/customproperty/v1beta1"
+	funnelv1beta1 "github.com/rossigee/provider-plausible/apis/funnel/v1beta1"
 	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
 	guestv1beta1 "github.com/rossigee/provider-plausible/apis/guest/v1beta1"
+	namespacedv1beta1 "github.com/rossigee/provider-plausible/apis/namespaced/v1beta1"
 	sharedlinkv1beta1 "github.com/rossigee/provider-plausible/apis/sharedlink/v1beta1"
 	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
 	teamv1beta1 "github.com/rossigee/provider-plausible/apis/team/v1beta1"
@@ -32,12 +34,14 @@ func init() {
 	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
 	AddToSchemes = append(AddToSchemes,
 		v1beta1.AddToScheme,
+		namespacedv1beta1.AddToScheme,
 		sitev1beta1.AddToScheme,
 		goalv1beta1.AddToScheme,
 		sharedlinkv1beta1.AddToScheme,
 		custompropertyv1beta1.AddToScheme,
 		guestv1beta1.AddToScheme,
 		teamv1beta1.AddToScheme,
+		funnelv1beta1.AddToScheme,
 	)
 }
 
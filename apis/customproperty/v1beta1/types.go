@@ -22,9 +22,13 @@ import (
 )
 
 // CustomPropertyParameters are the configurable fields of a CustomProperty.
+// +kubebuilder:validation:XValidation:rule="has(self.siteDomain) || has(self.siteDomainRef) || has(self.siteDomainSelector)",message="one of siteDomain, siteDomainRef or siteDomainSelector is required"
+// +kubebuilder:validation:XValidation:rule="!(has(self.siteDomainRef) && has(self.siteDomainSelector))",message="siteDomainRef and siteDomainSelector are mutually exclusive"
 type CustomPropertyParameters struct {
 	// SiteDomain is the domain of the site this custom property belongs to.
 	// This can be specified directly or via a reference/selector.
+	// +crossplane:generate:reference:type=github.com/rossigee/provider-plausible/apis/site/v1beta1.Site
+	// +crossplane:generate:reference:extractor=github.com/rossigee/provider-plausible/apis/site/v1beta1.Domain()
 	// +optional
 	SiteDomain *string `json:"siteDomain,omitempty"`
 
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A FunnelStep is a goal visitors must complete to move through a funnel.
+// +kubebuilder:validation:XValidation:rule="has(self.goalId) || has(self.goalIdRef) || has(self.goalIdSelector)",message="one of goalId, goalIdRef or goalIdSelector is required"
+type FunnelStep struct {
+	// GoalID is the ID of the goal of this step in Plausible.
+	// This can be specified directly or via a reference/selector.
+	// +crossplane:generate:reference:type=github.com/rossigee/provider-plausible/apis/goal/v1beta1.Goal
+	// +crossplane:generate:reference:extractor=github.com/rossigee/provider-plausible/apis/goal/v1beta1.GoalID()
+	// +optional
+	GoalID *string `json:"goalId,omitempty"`
+
+	// GoalIDRef references a Goal resource to retrieve its ID.
+	// +optional
+	GoalIDRef *xpv1.Reference `json:"goalIdRef,omitempty"`
+
+	// GoalIDSelector selects a Goal resource to retrieve its ID.
+	// +optional
+	GoalIDSelector *xpv1.Selector `json:"goalIdSelector,omitempty"`
+}
+
+// FunnelParameters are the configurable fields of a Funnel.
+// +kubebuilder:validation:XValidation:rule="has(self.siteDomain) || has(self.siteDomainRef) || has(self.siteDomainSelector)",message="one of siteDomain, siteDomainRef or siteDomainSelector is required"
+// +kubebuilder:validation:XValidation:rule="!(has(self.siteDomainRef) && has(self.siteDomainSelector))",message="siteDomainRef and siteDomainSelector are mutually exclusive"
+type FunnelParameters struct {
+	// SiteDomain is the domain of the site this funnel belongs to.
+	// This can be specified directly or via a reference/selector.
+	// +crossplane:generate:reference:type=github.com/rossigee/provider-plausible/apis/site/v1beta1.Site
+	// +crossplane:generate:reference:extractor=github.com/rossigee/provider-plausible/apis/site/v1beta1.Domain()
+	// +optional
+	SiteDomain *string `json:"siteDomain,omitempty"`
+
+	// SiteDomainRef references a Site resource to retrieve its domain.
+	// +optional
+	SiteDomainRef *xpv1.Reference `json:"siteDomainRef,omitempty"`
+
+	// SiteDomainSelector selects a Site resource to retrieve its domain.
+	// +optional
+	SiteDomainSelector *xpv1.Selector `json:"siteDomainSelector,omitempty"`
+
+	// Name is the name of the funnel, unique within its site.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Steps are the goals of the funnel, in the order visitors complete them.
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=8
+	Steps []FunnelStep `json:"steps"`
+}
+
+// FunnelObservation are the observable fields of a Funnel.
+type FunnelObservation struct {
+	// ID is the unique identifier of the funnel in Plausible.
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the funnel.
+	Name string `json:"name,omitempty"`
+
+	// GoalIDs are the IDs of the goals of the funnel's steps, in order.
+	GoalIDs []string `json:"goalIds,omitempty"`
+}
+
+// A FunnelSpec defines the desired state of a Funnel.
+type FunnelSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              FunnelParameters `json:"forProvider"`
+}
+
+// A FunnelStatus represents the observed state of a Funnel.
+type FunnelStatus struct {
+	xpv1.ManagedResourceStatus `json:",inline"`
+	AtProvider                 FunnelObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Funnel is a managed resource that represents a Plausible funnel.
+// Funnels are alpha: Plausible does not document the funnel endpoints of its
+// Sites API, so they may change without notice. The provider only reconciles
+// Funnels when it runs with --enable-alpha-funnels.
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".spec.forProvider.name"
+// +kubebuilder:printcolumn:name="FUNNEL-ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,plausible}
+type Funnel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FunnelSpec   `json:"spec"`
+	Status FunnelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FunnelList contains a list of Funnel
+type FunnelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Funnel `json:"items"`
+}
@@ -22,10 +22,14 @@ limitations under the License.
 
 // Remove existing manifests
 //go:generate rm -rf ../package/crds
+//go:generate rm -rf ../package/webhookconfigurations
 
 // Generate deepcopy, metadata, and RBAC files.
 //go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:allowDangerousTypes=true output:artifacts:config=../package/crds
 
+// Generate the webhook configurations of the validating webhooks.
+//go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen webhook paths=../internal/webhook/... output:artifacts:config=../package/webhookconfigurations
+
 // Generate crossplane-runtime methodsets (resource.Claim, etc)
 //go:generate go run -tags generate github.com/crossplane/crossplane-tools/cmd/angryjet generate-methodsets --header-file=../hack/boilerplate.go.txt ./...
 
@@ -0,0 +1,35 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+)
+
+// GoalID returns an extractor that yields the ID of a Goal as observed in
+// Plausible. Nothing is returned until the Goal has been observed, so
+// resources referencing it wait for the goal to exist.
+func GoalID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		g, ok := mg.(*Goal)
+		if !ok {
+			return ""
+		}
+		return g.Status.AtProvider.ID
+	}
+}
@@ -21,10 +21,35 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// A ReplacePolicy determines what happens when a field of a Goal changes.
+// Goals cannot be changed in Plausible, only replaced.
+// +kubebuilder:validation:Enum=Replace;Never
+type ReplacePolicy string
+
+const (
+	// ReplacePolicyReplace creates a new goal, moves the Goal over to it and
+	// deletes the old one.
+	ReplacePolicyReplace ReplacePolicy = "Replace"
+
+	// ReplacePolicyNever keeps the old goal and reports that it differs from
+	// the spec.
+	ReplacePolicyNever ReplacePolicy = "Never"
+)
+
 // GoalParameters are the configurable fields of a Goal.
+// +kubebuilder:validation:XValidation:rule="has(self.siteDomain) || has(self.siteDomainRef) || has(self.siteDomainSelector)",message="one of siteDomain, siteDomainRef or siteDomainSelector is required"
+// +kubebuilder:validation:XValidation:rule="!(has(self.siteDomainRef) && has(self.siteDomainSelector))",message="siteDomainRef and siteDomainSelector are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="self.goalType != 'event' || has(self.eventName)",message="event goals need an eventName"
+// +kubebuilder:validation:XValidation:rule="self.goalType != 'page' || has(self.pagePath)",message="page goals need a pagePath"
+// +kubebuilder:validation:XValidation:rule="self.goalType == 'event' || !has(self.eventName)",message="only event goals have an eventName"
+// +kubebuilder:validation:XValidation:rule="self.goalType == 'event' || !has(self.currency)",message="only event goals have a currency"
+// +kubebuilder:validation:XValidation:rule="self.goalType == 'page' || !has(self.pagePath)",message="only page goals have a pagePath"
+// +kubebuilder:validation:XValidation:rule="self.goalType == 'page' || !has(self.scrollThreshold)",message="only page goals have a scrollThreshold"
 type GoalParameters struct {
 	// SiteDomain is the domain of the site this goal belongs to.
 	// This can be specified directly or via a reference/selector.
+	// +crossplane:generate:reference:type=github.com/rossigee/provider-plausible/apis/site/v1beta1.Site
+	// +crossplane:generate:reference:extractor=github.com/rossigee/provider-plausible/apis/site/v1beta1.Domain()
 	// +optional
 	SiteDomain *string `json:"siteDomain,omitempty"`
 
@@ -45,9 +70,43 @@ type GoalParameters struct {
 	// +optional
 	EventName *string `json:"eventName,omitempty"`
 
-	// PagePath is required when GoalType is "page".
+	// PagePath is required when GoalType is "page". It may contain the
+	// wildcards * to match within a path segment and ** to match across
+	// segments, e.g. "/blog/**".
+	// +kubebuilder:validation:XValidation:rule="self.startsWith('/')",message="pagePath must start with /"
+	// +kubebuilder:validation:XValidation:rule="!self.contains('***')",message="pagePath wildcards are * and **"
 	// +optional
 	PagePath *string `json:"pagePath,omitempty"`
+
+	// DisplayName is the name of the goal shown in the Plausible dashboard.
+	// Plausible uses the event name or page path if it is not set.
+	// +optional
+	DisplayName *string `json:"displayName,omitempty"`
+
+	// Currency makes an event goal a revenue goal, reporting revenue in the
+	// given ISO 4217 currency code (e.g., "USD", "EUR").
+	// +kubebuilder:validation:Pattern=`^[A-Z]{3}$`
+	// +optional
+	Currency *string `json:"currency,omitempty"`
+
+	// ScrollThreshold makes a page goal complete only once a visitor has
+	// scrolled this percentage of the page.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ScrollThreshold *int `json:"scrollThreshold,omitempty"`
+
+	// CustomProps limits the goal to events carrying all of these custom
+	// properties with the given values.
+	// +optional
+	CustomProps map[string]string `json:"customProps,omitempty"`
+
+	// ReplacePolicy determines whether the goal is replaced when any of the
+	// fields above change. Defaults to Replace. A goal whose management
+	// policies do not allow creating and deleting it is never replaced.
+	// +kubebuilder:default=Replace
+	// +optional
+	ReplacePolicy ReplacePolicy `json:"replacePolicy,omitempty"`
 }
 
 // GoalObservation are the observable fields of a Goal.
@@ -64,6 +123,18 @@ type GoalObservation struct {
 	// PagePath if the goal is a page type.
 	PagePath string `json:"pagePath,omitempty"`
 
+	// DisplayName is the name of the goal shown in the Plausible dashboard.
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Currency if the goal is a revenue goal.
+	Currency string `json:"currency,omitempty"`
+
+	// ScrollThreshold if the goal is a scroll depth goal.
+	ScrollThreshold int `json:"scrollThreshold,omitempty"`
+
+	// CustomProps the goal is limited to.
+	CustomProps map[string]string `json:"customProps,omitempty"`
+
 	// CreatedAt is the timestamp when the goal was created.
 	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
 }
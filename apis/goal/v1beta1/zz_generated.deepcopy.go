@@ -87,6 +87,13 @@ func (in *GoalList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GoalObservation) DeepCopyInto(out *GoalObservation) {
 	*out = *in
+	if in.CustomProps != nil {
+		in, out := &in.CustomProps, &out.CustomProps
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.CreatedAt != nil {
 		in, out := &in.CreatedAt, &out.CreatedAt
 		*out = (*in).DeepCopy()
@@ -131,6 +138,28 @@ func (in *GoalParameters) DeepCopyInto(out *GoalParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.DisplayName != nil {
+		in, out := &in.DisplayName, &out.DisplayName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Currency != nil {
+		in, out := &in.Currency, &out.Currency
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScrollThreshold != nil {
+		in, out := &in.ScrollThreshold, &out.ScrollThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.CustomProps != nil {
+		in, out := &in.CustomProps, &out.CustomProps
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoalParameters.
@@ -22,9 +22,13 @@ import (
 )
 
 // GuestParameters are the configurable fields of a Guest.
+// +kubebuilder:validation:XValidation:rule="has(self.siteDomain) || has(self.siteDomainRef) || has(self.siteDomainSelector)",message="one of siteDomain, siteDomainRef or siteDomainSelector is required"
+// +kubebuilder:validation:XValidation:rule="!(has(self.siteDomainRef) && has(self.siteDomainSelector))",message="siteDomainRef and siteDomainSelector are mutually exclusive"
 type GuestParameters struct {
 	// SiteDomain is the domain of the site this guest should have access to.
 	// This can be specified directly or via a reference/selector.
+	// +crossplane:generate:reference:type=github.com/rossigee/provider-plausible/apis/site/v1beta1.Site
+	// +crossplane:generate:reference:extractor=github.com/rossigee/provider-plausible/apis/site/v1beta1.Domain()
 	// +optional
 	SiteDomain *string `json:"siteDomain,omitempty"`
 
@@ -43,6 +47,9 @@ type GuestParameters struct {
 
 	// Role defines the access level for the guest.
 	// Typically "viewer" for read-only access or "admin" for full access.
+	// Plausible cannot change the role of a guest, so changing it removes the
+	// guest and invites them again. A guest who had accepted loses access
+	// until they accept the new invitation.
 	// +kubebuilder:validation:Enum=viewer;admin
 	// +kubebuilder:default="viewer"
 	Role string `json:"role,omitempty"`
@@ -22,9 +22,13 @@ import (
 )
 
 // SharedLinkParameters are the configurable fields of a SharedLink.
+// +kubebuilder:validation:XValidation:rule="has(self.siteDomain) || has(self.siteDomainRef) || has(self.siteDomainSelector)",message="one of siteDomain, siteDomainRef or siteDomainSelector is required"
+// +kubebuilder:validation:XValidation:rule="!(has(self.siteDomainRef) && has(self.siteDomainSelector))",message="siteDomainRef and siteDomainSelector are mutually exclusive"
 type SharedLinkParameters struct {
 	// SiteDomain is the domain of the site this shared link belongs to.
 	// This can be specified directly or via a reference/selector.
+	// +crossplane:generate:reference:type=github.com/rossigee/provider-plausible/apis/site/v1beta1.Site
+	// +crossplane:generate:reference:extractor=github.com/rossigee/provider-plausible/apis/site/v1beta1.Domain()
 	// +optional
 	SiteDomain *string `json:"siteDomain,omitempty"`
 
@@ -43,8 +47,27 @@ type SharedLinkParameters struct {
 
 	// Password provides optional password protection for the shared link.
 	// If set, viewers must enter this password to access the dashboard.
+	// Deprecated: Use PasswordSecretRef or GeneratePassword instead, so the
+	// password is not stored in the spec.
 	// +optional
 	Password *string `json:"password,omitempty"`
+
+	// PasswordSecretRef selects a key of a Secret in the SharedLink's
+	// namespace that holds the password. When the Secret changes, the shared
+	// link is replaced with one protected by the new password. Plausible does
+	// not return the password of a link, so a change is only detected when
+	// writeConnectionSecretToRef is set, by comparing the password with the
+	// one published there. Takes precedence over GeneratePassword and
+	// Password.
+	// +optional
+	PasswordSecretRef *xpv1.LocalSecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// GeneratePassword protects the shared link with a random password that
+	// is published under the password key of the connection Secret. It
+	// requires writeConnectionSecretToRef, and a new password is generated
+	// if that key is removed. Takes precedence over Password.
+	// +optional
+	GeneratePassword *bool `json:"generatePassword,omitempty"`
 }
 
 // SharedLinkObservation are the observable fields of a SharedLink.
@@ -63,6 +86,7 @@ type SharedLinkObservation struct {
 }
 
 // A SharedLinkSpec defines the desired state of a SharedLink.
+// +kubebuilder:validation:XValidation:rule="has(self.writeConnectionSecretToRef) || !has(self.forProvider.generatePassword) || !self.forProvider.generatePassword",message="generatePassword requires writeConnectionSecretToRef"
 type SharedLinkSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              SharedLinkParameters `json:"forProvider"`
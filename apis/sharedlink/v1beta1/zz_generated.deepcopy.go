@@ -126,6 +126,16 @@ func (in *SharedLinkParameters) DeepCopyInto(out *SharedLinkParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v2.LocalSecretKeySelector)
+		**out = **in
+	}
+	if in.GeneratePassword != nil {
+		in, out := &in.GeneratePassword, &out.GeneratePassword
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedLinkParameters.
@@ -21,27 +21,101 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// AnnotationAllowDeletion, when set to "true" on a Site, allows the site to be
+// deleted in Plausible once even though deletion protection is enabled. The
+// provider removes the annotation as soon as it has deleted the site.
+const AnnotationAllowDeletion = "site.plausible.m.crossplane.io/allow-deletion"
+
 // SiteParameters are the configurable fields of a Site.
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.teamID) || (has(self.teamID) && self.teamID == oldSelf.teamID)",message="teamID is immutable once set"
 type SiteParameters struct {
 	// Domain is the domain name of the site in Plausible.
-	// This is the primary identifier for the site.
+	// Changing it renames the existing site; resources that reference the
+	// site follow the new domain once it has been observed.
 	// +kubebuilder:validation:Required
 	Domain string `json:"domain"`
 
-	// NewDomain is used when updating the domain of an existing site.
-	// This field is only used during updates and should be left empty during creation.
+	// NewDomain renames an existing site.
+	// Deprecated: change domain instead. If set, it takes precedence over
+	// domain.
 	// +optional
 	NewDomain *string `json:"newDomain,omitempty"`
 
 	// TeamID associates the site with a specific team.
 	// If not provided, the site will be associated with the default team.
+	// It cannot be changed once set.
+	// +crossplane:generate:reference:type=github.com/rossigee/provider-plausible/apis/team/v1beta1.Team
 	// +optional
 	TeamID *string `json:"teamID,omitempty"`
 
+	// TeamIDRef references a Team resource to retrieve its ID.
+	// +optional
+	TeamIDRef *xpv1.Reference `json:"teamIDRef,omitempty"`
+
+	// TeamIDSelector selects a Team resource to retrieve its ID.
+	// +optional
+	TeamIDSelector *xpv1.Selector `json:"teamIDSelector,omitempty"`
+
 	// Timezone for the site. Must be a valid IANA timezone string.
-	// If not provided, defaults to UTC.
+	// If not provided, Plausible's default is adopted.
 	// +optional
 	Timezone *string `json:"timezone,omitempty"`
+
+	// TrackerScriptConfiguration selects the optional features of the
+	// tracker script. Features that are not set adopt Plausible's defaults.
+	// +optional
+	TrackerScriptConfiguration *TrackerScriptConfiguration `json:"trackerScriptConfiguration,omitempty"`
+
+	// DeletionProtection refuses to delete the site in Plausible, along with
+	// all of its analytics, when the Site is deleted. If not set, the
+	// provider's --deletion-protection flag applies, which is on by default.
+	// Annotate the Site with site.plausible.m.crossplane.io/allow-deletion:
+	// "true" to delete a protected site once; the annotation is removed as
+	// soon as the site has been deleted.
+	// +optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+}
+
+// TrackerScriptConfiguration configures the Plausible tracker script of a
+// site.
+type TrackerScriptConfiguration struct {
+	// InstallationType is how the tracker script is installed on the site.
+	// +kubebuilder:validation:Enum=manual;wordpress;gtm;npm
+	// +optional
+	InstallationType *string `json:"installationType,omitempty"`
+
+	// OutboundLinks tracks clicks on links to other sites.
+	// +optional
+	OutboundLinks *bool `json:"outboundLinks,omitempty"`
+
+	// FileDownloads tracks clicks on links to downloadable files.
+	// +optional
+	FileDownloads *bool `json:"fileDownloads,omitempty"`
+
+	// FormSubmissions tracks form submissions.
+	// +optional
+	FormSubmissions *bool `json:"formSubmissions,omitempty"`
+
+	// HashBasedRouting counts changes to the URL fragment as pageviews, for
+	// single-page applications that route by hash.
+	// +optional
+	HashBasedRouting *bool `json:"hashBasedRouting,omitempty"`
+
+	// Track404Pages tracks visits to pages that do not exist.
+	// +optional
+	Track404Pages *bool `json:"track404Pages,omitempty"`
+
+	// TaggedEvents tracks clicks on elements tagged with CSS class names.
+	// +optional
+	TaggedEvents *bool `json:"taggedEvents,omitempty"`
+
+	// RevenueTracking allows custom events to carry revenue.
+	// +optional
+	RevenueTracking *bool `json:"revenueTracking,omitempty"`
+
+	// PageviewProps allows pageviews to carry custom properties.
+	// +optional
+	PageviewProps *bool `json:"pageviewProps,omitempty"`
 }
 
 // SiteObservation are the observable fields of a Site.
@@ -52,9 +126,20 @@ type SiteObservation struct {
 	// Domain is the current domain of the site.
 	Domain string `json:"domain,omitempty"`
 
+	// PreviousDomain is the domain the site had before it was last renamed.
+	// Plausible keeps accepting events for it for a while after a rename.
+	PreviousDomain string `json:"previousDomain,omitempty"`
+
 	// TeamID is the ID of the team the site belongs to.
 	TeamID string `json:"teamID,omitempty"`
 
+	// Timezone is the current timezone of the site.
+	Timezone string `json:"timezone,omitempty"`
+
+	// TrackerScriptConfiguration is the current tracker script configuration
+	// of the site.
+	TrackerScriptConfiguration *TrackerScriptConfiguration `json:"trackerScriptConfiguration,omitempty"`
+
 	// CreatedAt is the timestamp when the site was created.
 	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
 
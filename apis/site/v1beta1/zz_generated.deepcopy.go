@@ -21,6 +21,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -86,6 +87,11 @@ func (in *SiteList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SiteObservation) DeepCopyInto(out *SiteObservation) {
 	*out = *in
+	if in.TrackerScriptConfiguration != nil {
+		in, out := &in.TrackerScriptConfiguration, &out.TrackerScriptConfiguration
+		*out = new(TrackerScriptConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CreatedAt != nil {
 		in, out := &in.CreatedAt, &out.CreatedAt
 		*out = (*in).DeepCopy()
@@ -119,11 +125,31 @@ func (in *SiteParameters) DeepCopyInto(out *SiteParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TeamIDRef != nil {
+		in, out := &in.TeamIDRef, &out.TeamIDRef
+		*out = new(v2.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TeamIDSelector != nil {
+		in, out := &in.TeamIDSelector, &out.TeamIDSelector
+		*out = new(v2.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Timezone != nil {
 		in, out := &in.Timezone, &out.Timezone
 		*out = new(string)
 		**out = **in
 	}
+	if in.TrackerScriptConfiguration != nil {
+		in, out := &in.TrackerScriptConfiguration, &out.TrackerScriptConfiguration
+		*out = new(TrackerScriptConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteParameters.
@@ -169,3 +195,63 @@ func (in *SiteStatus) DeepCopy() *SiteStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrackerScriptConfiguration) DeepCopyInto(out *TrackerScriptConfiguration) {
+	*out = *in
+	if in.InstallationType != nil {
+		in, out := &in.InstallationType, &out.InstallationType
+		*out = new(string)
+		**out = **in
+	}
+	if in.OutboundLinks != nil {
+		in, out := &in.OutboundLinks, &out.OutboundLinks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FileDownloads != nil {
+		in, out := &in.FileDownloads, &out.FileDownloads
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FormSubmissions != nil {
+		in, out := &in.FormSubmissions, &out.FormSubmissions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HashBasedRouting != nil {
+		in, out := &in.HashBasedRouting, &out.HashBasedRouting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Track404Pages != nil {
+		in, out := &in.Track404Pages, &out.Track404Pages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TaggedEvents != nil {
+		in, out := &in.TaggedEvents, &out.TaggedEvents
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RevenueTracking != nil {
+		in, out := &in.RevenueTracking, &out.RevenueTracking
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PageviewProps != nil {
+		in, out := &in.PageviewProps, &out.PageviewProps
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrackerScriptConfiguration.
+func (in *TrackerScriptConfiguration) DeepCopy() *TrackerScriptConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TrackerScriptConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -24,11 +24,18 @@ import (
 // TeamParameters are the configurable fields of a Team.
 // Note: Teams are read-only resources that represent existing teams in Plausible.
 // This resource is primarily for discovery and reference purposes.
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.teamID) || (has(self.teamID) && self.teamID == oldSelf.teamID)",message="teamID is immutable once set"
 type TeamParameters struct {
 	// TeamID is the unique identifier of the team in Plausible.
-	// This is used to filter and discover existing teams.
+	// This is used to filter and discover existing teams. It cannot be
+	// changed once set.
 	// +optional
 	TeamID *string `json:"teamID,omitempty"`
+
+	// Name is the display name of the team in Plausible.
+	// Used to discover the team when TeamID is not known.
+	// +optional
+	Name *string `json:"name,omitempty"`
 }
 
 // TeamObservation are the observable fields of a Team.
@@ -41,6 +41,8 @@ func addKnownTypes(s *runtime.Scheme) error {
 	s.AddKnownTypes(SchemeGroupVersion,
 		&ProviderConfig{},
 		&ProviderConfigList{},
+		&ClusterProviderConfig{},
+		&ClusterProviderConfigList{},
 		&ProviderConfigUsage{},
 		&ProviderConfigUsageList{},
 	)
@@ -32,6 +32,48 @@ type ProviderConfigSpec struct {
 	// For self-hosted instances, this is the URL of your instance.
 	// +optional
 	BaseURL *string `json:"baseURL,omitempty"`
+
+	// Transport tunes how the provider talks to the Plausible API, including
+	// client-side rate limiting and retries.
+	// +optional
+	Transport *TransportConfig `json:"transport,omitempty"`
+}
+
+// TransportConfig tunes the HTTP transport used to reach the Plausible API.
+type TransportConfig struct {
+	// Timeout for a single HTTP request, including reading the response.
+	// Defaults to 30s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// MaxRetries is how many times a request that failed with status 429 or
+	// 5xx is retried. Only idempotent requests are retried. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries *int `json:"maxRetries,omitempty"`
+
+	// MinBackoff is the delay before the first retry when Plausible does not
+	// send a Retry-After header. It doubles with every retry. Defaults to 1s.
+	// +optional
+	MinBackoff *metav1.Duration `json:"minBackoff,omitempty"`
+
+	// MaxBackoff caps the delay between retries. A request whose Retry-After
+	// is longer than this fails instead of blocking. Defaults to 30s.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// RequestsPerMinute is the sustained rate of requests made with this
+	// configuration's API key, shared by every resource that uses it.
+	// Plausible allows 600 requests per hour by default. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RequestsPerMinute *int `json:"requestsPerMinute,omitempty"`
+
+	// Burst is how many requests may be made back to back before
+	// RequestsPerMinute applies. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst *int `json:"burst,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -75,8 +117,35 @@ type ProviderConfigList struct {
 
 // +kubebuilder:object:root=true
 
+// A ClusterProviderConfig configures a Plausible provider for managed
+// resources in any namespace.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,plausible}
+// +kubebuilder:storageversion
+type ClusterProviderConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderConfigSpec   `json:"spec"`
+	Status ProviderConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterProviderConfigList contains a list of ClusterProviderConfig.
+type ClusterProviderConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterProviderConfig `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
 // A ProviderConfigUsage indicates that a resource is using a ProviderConfig.
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="CONFIG-KIND",type="string",JSONPath=".providerConfigRef.kind"
 // +kubebuilder:printcolumn:name="CONFIG-NAME",type="string",JSONPath=".providerConfigRef.name"
 // +kubebuilder:printcolumn:name="RESOURCE-KIND",type="string",JSONPath=".resourceRef.kind"
 // +kubebuilder:printcolumn:name="RESOURCE-NAME",type="string",JSONPath=".resourceRef.name"
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -32,6 +33,7 @@ import (
 	"github.com/rossigee/provider-plausible/internal/features"
 	"github.com/rossigee/provider-plausible/internal/tracing"
 	"github.com/rossigee/provider-plausible/internal/version"
+	plausiblewebhook "github.com/rossigee/provider-plausible/internal/webhook"
 	"gopkg.in/alecthomas/kingpin.v2"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -39,6 +41,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 func main() {
@@ -51,6 +55,12 @@ func main() {
 		maxReconcileRate         = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
 		syncPeriod               = app.Flag("sync", "How often all resources will be double-checked for drift from the desired state.").Short('s').Default("1h").Duration()
 		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for management policies.").Default("true").OverrideDefaultFromEnvar("ENABLE_MANAGEMENT_POLICIES").Bool()
+		metricsBindAddress       = app.Flag("metrics-bind-address", "The address the metrics endpoint binds to. Set to 0 to disable it.").Default(":8080").OverrideDefaultFromEnvar("METRICS_BIND_ADDRESS").String()
+		enableWebhooks           = app.Flag("enable-webhooks", "Serve the validating admission webhooks of the managed resources.").Default("true").OverrideDefaultFromEnvar("ENABLE_WEBHOOKS").Bool()
+		webhookPort              = app.Flag("webhook-port", "The port the webhook server listens on.").Default("9443").Int()
+		certsDir                 = app.Flag("certs-dir", "The directory that contains the webhook server key and certificate, which are reloaded when they change.").Default("/tls/server").OverrideDefaultFromEnvar("TLS_SERVER_CERTS_DIR").String()
+		enableAlphaFunnels       = app.Flag("enable-alpha-funnels", "Enable the alpha Funnel kind, which uses undocumented Plausible API endpoints.").Default("false").OverrideDefaultFromEnvar("ENABLE_ALPHA_FUNNELS").Bool()
+		deletionProtection       = app.Flag("deletion-protection", "Refuse to delete Sites in Plausible unless their deletionProtection is false or they are annotated to allow deletion.").Default("true").OverrideDefaultFromEnvar("DELETION_PROTECTION").Bool()
 	)
 
 	kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -76,6 +86,10 @@ func main() {
 		"leader-election", *leaderElection,
 		"leader-election-namespace", *leaderElectionNS,
 		"management-policies", *enableManagementPolicies,
+		"deletion-protection", *deletionProtection,
+		"alpha-funnels", *enableAlphaFunnels,
+		"metrics-bind-address", *metricsBindAddress,
+		"webhooks", *enableWebhooks,
 		"debug-mode", *debug)
 
 	log.Debug("Detailed startup configuration",
@@ -100,6 +114,16 @@ func main() {
 		Cache: cache.Options{
 			SyncPeriod: syncPeriod,
 		},
+		Metrics: metricsserver.Options{
+			BindAddress: *metricsBindAddress,
+		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    *webhookPort,
+			CertDir: *certsDir,
+			TLSOpts: []func(*tls.Config){
+				func(c *tls.Config) { c.MinVersion = tls.VersionTLS12 },
+			},
+		}),
 		Scheme:                     s,
 		LeaderElection:             *leaderElection,
 		LeaderElectionID:           "crossplane-leader-election-provider-plausible",
@@ -125,10 +149,31 @@ func main() {
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaManagementPolicies)
 	}
 
+	if *enableAlphaFunnels {
+		o.Features.Enable(features.EnableAlphaFunnels)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaFunnels)
+	}
+
+	if *deletionProtection {
+		o.Features.Enable(features.EnableDeletionProtection)
+		log.Info("Feature enabled", "flag", features.EnableDeletionProtection)
+	}
+
 	if err := plausiblecontroller.Setup(mgr, o); err != nil {
 		kingpin.FatalIfError(err, "Cannot setup Plausible controllers")
 	}
 
+	// Without a certificate the webhook server cannot start, which would stop
+	// the controllers too, so the webhooks are skipped instead. Crossplane
+	// provisions the certificate for providers whose package has webhooks.
+	if *enableWebhooks {
+		if _, err := os.Stat(filepath.Join(*certsDir, "tls.crt")); err != nil {
+			log.Info("Admission webhooks disabled: no webhook server certificate", "certs-dir", *certsDir, "error", err.Error())
+		} else {
+			kingpin.FatalIfError(plausiblewebhook.Setup(mgr), "Cannot setup Plausible webhooks")
+		}
+	}
+
 	kingpin.FatalIfError(mgr.AddHealthzCheck("healthz", healthz.Ping), "Cannot add health check")
 	kingpin.FatalIfError(mgr.AddReadyzCheck("readyz", healthz.Ping), "Cannot add ready check")
 
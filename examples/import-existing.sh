@@ -16,7 +16,7 @@ echo "Checking provider status..."
 kubectl get providers.pkg.crossplane.io provider-plausible -o wide
 
 echo "Checking provider configuration..."
-kubectl get providerconfigs.plausible.crossplane.io default -o yaml
+kubectl get clusterproviderconfigs.plausible.crossplane.io default -o yaml
 
 echo "Creating sites for import..."
 for domain in "${DOMAINS[@]}"; do
@@ -35,6 +35,7 @@ spec:
     domain: "$domain"
     timezone: Asia/Bangkok
   providerConfigRef:
+    kind: ClusterProviderConfig
     name: default
 EOF
 done
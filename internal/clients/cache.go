@@ -0,0 +1,238 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// listCacheTTL is how long a list response is reused. It is shorter than the
+// default poll interval, so every poll sees fresh data, but long enough that
+// the children of one site observed in the same poll share a single list.
+const listCacheTTL = 30 * time.Second
+
+// Collections of site children whose list responses are cached.
+const (
+	collectionGoals            = "goals"
+	collectionSharedLinks      = "shared-links"
+	collectionCustomProperties = "custom-props"
+	collectionGuests           = "guests"
+	collectionFunnels          = "funnels"
+)
+
+// caches and siteIndexes hold the list cache and site index of every
+// ProviderConfig seen so far.
+var (
+	caches      = newRegistry(newListCache)
+	siteIndexes = newRegistry(newSiteIndex)
+)
+
+// registry shares one T between all clients of a ProviderConfig.
+type registry[T any] struct {
+	mu    sync.Mutex
+	newFn func() *T
+	items map[string]*T
+}
+
+func newRegistry[T any](newFn func() *T) *registry[T] {
+	return &registry[T]{newFn: newFn, items: map[string]*T{}}
+}
+
+// get returns the T for key. Clients without a key, e.g. in tests, get one of
+// their own.
+func (r *registry[T]) get(key string) *T {
+	if key == "" {
+		return r.newFn()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[key]
+	if !ok {
+		item = r.newFn()
+		r.items[key] = item
+	}
+	return item
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// listCache holds recent list responses, keyed by collection and site.
+//
+// Concurrent misses for the same list share a single call. Every invalidation
+// bumps the generation of the cache, and a list response is only stored if no
+// invalidation happened while it was being fetched, so a list that started
+// before a write cannot put the data it read back after the write.
+type listCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	now        func() time.Time
+	entries    map[string]cacheEntry
+	generation uint64
+	calls      singleflight.Group
+}
+
+func newListCache() *listCache {
+	return &listCache{
+		ttl:     listCacheTTL,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+func cacheKey(collection, siteDomain string) string {
+	return collection + "/" + siteDomain
+}
+
+// get returns the cached value of key, and the current generation of the
+// cache.
+func (c *listCache) get(key string) (interface{}, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+	if c.now().After(e.expires) {
+		delete(c.entries, key)
+		return nil, c.generation, false
+	}
+	return e.value, c.generation, true
+}
+
+// set stores value under key unless the cache was invalidated since
+// generation. It also drops any expired entries, so lists of sites that are
+// no longer observed do not accumulate.
+func (c *listCache) set(key string, value interface{}, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if generation != c.generation {
+		return
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+// invalidate drops the cached list of a collection for one site.
+func (c *listCache) invalidate(collection, siteDomain string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.entries, cacheKey(collection, siteDomain))
+}
+
+// invalidateCollection drops the cached lists of a collection for every site.
+// It is used when the site a change applies to is not known.
+func (c *listCache) invalidateCollection(collection string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for k := range c.entries {
+		if strings.HasPrefix(k, collection+"/") {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// cachedList returns the cached list of a collection for a site, calling list
+// to fetch it if it is missing or stale. Callers get their own copy of the
+// slice, so they cannot modify the cached one.
+func cachedList[T any](c *listCache, collection, siteDomain string, list func() ([]T, error)) ([]T, error) {
+	key := cacheKey(collection, siteDomain)
+	v, generation, ok := c.get(key)
+	if !ok {
+		// Callers that miss after an invalidation must not join a call that
+		// started before it, so the generation is part of the call key.
+		var err error
+		v, err, _ = c.calls.Do(fmt.Sprintf("%s@%d", key, generation), func() (interface{}, error) {
+			items, err := list()
+			if err != nil {
+				return nil, err
+			}
+			c.set(key, items, generation)
+			return items, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append([]T(nil), v.([]T)...), nil
+}
+
+// siteIndex maps site domains to IDs, so that a site whose ID is already known
+// can be fetched directly rather than looked up by domain.
+type siteIndex struct {
+	mu      sync.Mutex
+	ids     map[string]string
+	domains map[string]string
+}
+
+func newSiteIndex() *siteIndex {
+	return &siteIndex{ids: map[string]string{}, domains: map[string]string{}}
+}
+
+func (i *siteIndex) lookup(domain string) (string, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	id, ok := i.ids[domain]
+	return id, ok
+}
+
+// add records the domain of each site, replacing any domain previously
+// recorded for the same ID. Sites without an ID are ignored.
+func (i *siteIndex) add(sites ...Site) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, s := range sites {
+		if s.ID == "" || s.Domain == "" {
+			continue
+		}
+		if old, ok := i.domains[s.ID]; ok {
+			delete(i.ids, old)
+		}
+		i.ids[s.Domain] = s.ID
+		i.domains[s.ID] = s.Domain
+	}
+}
+
+// remove forgets a site, given either its ID or its domain.
+func (i *siteIndex) remove(idOrDomain string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if domain, ok := i.domains[idOrDomain]; ok {
+		delete(i.ids, domain)
+		delete(i.domains, idOrDomain)
+	}
+	if id, ok := i.ids[idOrDomain]; ok {
+		delete(i.domains, id)
+		delete(i.ids, idOrDomain)
+	}
+}
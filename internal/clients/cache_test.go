@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func goalServer(t *testing.T, lists *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			*lists++
+			_ = json.NewEncoder(w).Encode(ListGoalsResponse{Goals: []Goal{
+				{ID: "1", GoalType: "event", EventName: "Signup"},
+				{ID: "2", GoalType: "page", PagePath: "/pricing"},
+			}})
+		case http.MethodPut:
+			_ = json.NewEncoder(w).Encode(Goal{ID: "3", GoalType: "event", EventName: "Purchase"})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestListCacheSharedAcrossClients(t *testing.T) {
+	lists := 0
+	server := goalServer(t, &lists)
+	defer server.Close()
+
+	cfg := Config{BaseURL: server.URL, APIKey: "test-key", ProviderConfigKey: ProviderConfigKey("ClusterProviderConfig", "", t.Name())}
+	ctx := context.Background()
+
+	// Observing several goals of one site, from separate clients as the
+	// controllers would, costs a single list call.
+	for _, id := range []string{"1", "2", "missing"} {
+		if _, err := NewClient(cfg).GetGoal(ctx, "example.com", id); err != nil {
+			t.Fatalf("GetGoal(%q) unexpected error: %v", id, err)
+		}
+	}
+	if lists != 1 {
+		t.Errorf("GetGoal() made %d list calls, want 1", lists)
+	}
+
+	// Another site is cached separately.
+	if _, err := NewClient(cfg).ListGoals(ctx, "other.example.com"); err != nil {
+		t.Fatalf("ListGoals() unexpected error: %v", err)
+	}
+	if lists != 2 {
+		t.Errorf("ListGoals() for another site made %d list calls in total, want 2", lists)
+	}
+}
+
+func TestListCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]func(c *Client) error{
+		"CreateGoal": func(c *Client) error {
+			_, err := c.CreateGoal(ctx, "example.com", CreateGoalRequest{GoalType: "event", EventName: "Purchase"})
+			return err
+		},
+		"DeleteGoal": func(c *Client) error {
+			return c.DeleteGoal(ctx, "1")
+		},
+	}
+
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			lists := 0
+			server := goalServer(t, &lists)
+			defer server.Close()
+
+			c := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+			if _, err := c.ListGoals(ctx, "example.com"); err != nil {
+				t.Fatalf("ListGoals() unexpected error: %v", err)
+			}
+			if err := change(c); err != nil {
+				t.Fatalf("%s() unexpected error: %v", name, err)
+			}
+			if _, err := c.ListGoals(ctx, "example.com"); err != nil {
+				t.Fatalf("ListGoals() unexpected error: %v", err)
+			}
+			if lists != 2 {
+				t.Errorf("ListGoals() after %s made %d list calls in total, want 2", name, lists)
+			}
+		})
+	}
+}
+
+func TestListCacheExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newListCache()
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	list := func() ([]Goal, error) {
+		calls++
+		return []Goal{{ID: "1"}}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := cachedList(c, collectionGoals, "example.com", list)
+		if err != nil {
+			t.Fatalf("cachedList() unexpected error: %v", err)
+		}
+		// Callers must not be able to modify the cached slice.
+		got[0].ID = "modified"
+	}
+	if calls != 1 {
+		t.Errorf("cachedList() within the TTL made %d calls, want 1", calls)
+	}
+
+	now = now.Add(listCacheTTL + time.Second)
+	got, err := cachedList(c, collectionGoals, "example.com", list)
+	if err != nil {
+		t.Fatalf("cachedList() unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("cachedList() after the TTL made %d calls, want 2", calls)
+	}
+	if got[0].ID != "1" {
+		t.Errorf("cachedList() returned ID %q, want %q", got[0].ID, "1")
+	}
+}
+
+func TestListCacheConcurrentMisses(t *testing.T) {
+	c := newListCache()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	list := func() ([]Goal, error) {
+		calls.Add(1)
+		<-release
+		return []Goal{{ID: "1"}}, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			if _, err := cachedList(c, collectionGoals, "example.com", list); err != nil {
+				t.Errorf("cachedList() unexpected error: %v", err)
+			}
+		})
+	}
+	// Wait for the first call to start, and give the others time to join it.
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("concurrent cachedList() made %d calls, want 1", got)
+	}
+}
+
+func TestListCacheInvalidatedWhileListing(t *testing.T) {
+	c := newListCache()
+
+	// A list that started before a write and returns after the write's
+	// invalidation must not be cached.
+	stale := func() ([]Goal, error) {
+		c.invalidate(collectionGoals, "example.com")
+		return []Goal{{ID: "stale"}}, nil
+	}
+	if _, err := cachedList(c, collectionGoals, "example.com", stale); err != nil {
+		t.Fatalf("cachedList() unexpected error: %v", err)
+	}
+
+	got, err := cachedList(c, collectionGoals, "example.com", func() ([]Goal, error) {
+		return []Goal{{ID: "fresh"}}, nil
+	})
+	if err != nil {
+		t.Fatalf("cachedList() unexpected error: %v", err)
+	}
+	if got[0].ID != "fresh" {
+		t.Errorf("cachedList() after an invalidation returned ID %q, want %q", got[0].ID, "fresh")
+	}
+}
+
+func TestListCachePrunesExpiredEntries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newListCache()
+	c.now = func() time.Time { return now }
+
+	list := func() ([]Goal, error) { return nil, nil }
+	for _, domain := range []string{"a.example.com", "b.example.com"} {
+		if _, err := cachedList(c, collectionGoals, domain, list); err != nil {
+			t.Fatalf("cachedList() unexpected error: %v", err)
+		}
+	}
+
+	now = now.Add(listCacheTTL + time.Second)
+	if _, err := cachedList(c, collectionGoals, "c.example.com", list); err != nil {
+		t.Fatalf("cachedList() unexpected error: %v", err)
+	}
+	if len(c.entries) != 1 {
+		t.Errorf("cachedList() kept %d entries, want 1", len(c.entries))
+	}
+}
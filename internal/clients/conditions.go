@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons a managed resource may be unavailable because of a Plausible API
+// error.
+const (
+	ReasonUnauthorized      xpv1.ConditionReason = "Unauthorized"
+	ReasonForbidden         xpv1.ConditionReason = "Forbidden"
+	ReasonConflict          xpv1.ConditionReason = "Conflict"
+	ReasonInvalidParameters xpv1.ConditionReason = "InvalidParameters"
+)
+
+// TypeDrifted is the type of a condition reporting that an external resource
+// differs from its spec in a way the provider deliberately does not correct.
+// Such a resource is reported as up to date, since there is nothing an update
+// could do about it.
+const TypeDrifted xpv1.ConditionType = "Drifted"
+
+// ReasonMatchesSpec is the reason of a Drifted condition that was cleared.
+const ReasonMatchesSpec xpv1.ConditionReason = "MatchesSpec"
+
+// SetDrifted sets the Drifted condition of mg with the given reason and
+// message if drifted is true. Otherwise it clears a Drifted condition that
+// was set before, leaving resources that never drifted without one.
+func SetDrifted(mg resource.Conditioned, drifted bool, reason xpv1.ConditionReason, msg string) {
+	if drifted {
+		mg.SetConditions(xpv1.Condition{
+			Type:               TypeDrifted,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reason,
+			Message:            msg,
+		})
+		return
+	}
+	if mg.GetCondition(TypeDrifted).Status == corev1.ConditionTrue {
+		mg.SetConditions(xpv1.Condition{
+			Type:               TypeDrifted,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             ReasonMatchesSpec,
+		})
+	}
+}
+
+// ErrorCondition returns a Ready condition explaining err when it is an API
+// error the user has to act on. Transient errors such as rate limiting or
+// server failures return false, since they say nothing about the resource
+// itself and are retried anyway.
+func ErrorCondition(err error) (xpv1.Condition, bool) {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return xpv1.Condition{}, false
+	}
+
+	var reason xpv1.ConditionReason
+	msg := e.Message
+	switch {
+	case IsUnauthorized(err):
+		reason = ReasonUnauthorized
+		msg = "Plausible rejected the API key; check the credentials of the ProviderConfig"
+	case IsForbidden(err):
+		reason = ReasonForbidden
+		msg = "the API key does not have access to this Plausible API: " + e.Message
+	case IsConflict(err):
+		reason = ReasonConflict
+	case IsValidation(err):
+		reason = ReasonInvalidParameters
+	default:
+		return xpv1.Condition{}, false
+	}
+
+	c := xpv1.Unavailable().WithMessage(msg)
+	c.Reason = reason
+	return c, true
+}
+
+// WithErrorConditions wraps an ExternalClient so that any API error it
+// returns is also reflected in the Ready condition of the managed resource,
+// rather than only in the Synced condition as an opaque wrapped string.
+func WithErrorConditions(ec managed.ExternalClient) managed.ExternalClient {
+	return &conditionedClient{ExternalClient: ec}
+}
+
+type conditionedClient struct {
+	managed.ExternalClient
+}
+
+func (c *conditionedClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := c.ExternalClient.Observe(ctx, mg)
+	return o, setErrorCondition(mg, err)
+}
+
+func (c *conditionedClient) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, err := c.ExternalClient.Create(ctx, mg)
+	return cr, setErrorCondition(mg, err)
+}
+
+func (c *conditionedClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := c.ExternalClient.Update(ctx, mg)
+	return u, setErrorCondition(mg, err)
+}
+
+func (c *conditionedClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	d, err := c.ExternalClient.Delete(ctx, mg)
+	return d, setErrorCondition(mg, err)
+}
+
+func setErrorCondition(mg resource.Managed, err error) error {
+	if cond, ok := ErrorCondition(err); ok {
+		mg.SetConditions(cond)
+	}
+	return err
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// APIError is returned for any response from the Plausible API with a status
+// code of 400 or above.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Message is the error reported by Plausible, or the raw response body if
+	// it was not a Plausible error document.
+	Message string
+
+	// Method and Path identify the request that failed.
+	Method string
+	Path   string
+
+	// RetryAfter is how long Plausible asked us to wait before retrying, as
+	// given by the Retry-After header. It is zero if the header was absent.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: API request failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// newAPIError builds an APIError from a failed response. The body is read
+// but not closed.
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	// Plausible reports errors as {"error": "..."}; fall back to the raw body
+	// for anything else, e.g. an HTML page from a proxy in front of it.
+	var doc struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &doc); err == nil && doc.Error != "" {
+		e.Message = doc.Error
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return e
+}
+
+// parseRetryAfter parses a Retry-After header, which may be either a number
+// of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0
+		}
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// hasStatus returns true if err is an APIError with one of the given status
+// codes.
+func hasStatus(err error, codes ...int) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	for _, c := range codes {
+		if e.StatusCode == c {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound returns true if the error indicates the resource was not found
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized returns true if the error indicates the API key was missing
+// or rejected
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden returns true if the error indicates the API key is valid but
+// lacks access to the requested API, e.g. the Sites API is not enabled
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusPaymentRequired, http.StatusForbidden)
+}
+
+// IsRateLimited returns true if the error indicates the API key has exceeded
+// its request quota
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsConflict returns true if the error indicates the request conflicts with
+// an existing resource, e.g. a site with the same domain
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsValidation returns true if the error indicates Plausible rejected the
+// request parameters
+func IsValidation(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
+
+// IsUnreachable returns true if the error indicates the API could not be
+// reached at all, e.g. a DNS or connection failure
+func IsUnreachable(err error) bool {
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
+
+// RetryAfter returns how long Plausible asked us to wait before retrying the
+// request that caused err, or zero if it did not say.
+func RetryAfter(err error) time.Duration {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return 0
+	}
+	return e.RetryAfter
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		want   *APIError
+	}{
+		{
+			name:   "plausible error document",
+			status: http.StatusUnprocessableEntity,
+			body:   `{"error":"domain has already been taken"}`,
+			want: &APIError{
+				StatusCode: http.StatusUnprocessableEntity,
+				Message:    "domain has already been taken",
+				Method:     "GET",
+				Path:       "/api/v1/sites",
+			},
+		},
+		{
+			name:   "plain body",
+			status: http.StatusBadGateway,
+			body:   "<html>bad gateway</html>\n",
+			want: &APIError{
+				StatusCode: http.StatusBadGateway,
+				Message:    "<html>bad gateway</html>",
+				Method:     "GET",
+				Path:       "/api/v1/sites",
+			},
+		},
+		{
+			name:   "empty body with retry-after",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{"Retry-After": "30"},
+			want: &APIError{
+				StatusCode: http.StatusTooManyRequests,
+				Message:    "Too Many Requests",
+				Method:     "GET",
+				Path:       "/api/v1/sites",
+				RetryAfter: 30 * time.Second,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+			_, err := client.ListSites(context.Background())
+
+			var got *APIError
+			if !errors.As(err, &got) {
+				t.Fatalf("ListSites() error = %v, want *APIError", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ListSites() error -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		value string
+		want  time.Duration
+	}{
+		"Empty":       {value: "", want: 0},
+		"Seconds":     {value: "120", want: 2 * time.Minute},
+		"Negative":    {value: "-5", want: 0},
+		"HTTPDate":    {value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		"DateInPast":  {value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		"Unparseable": {value: "soon", want: 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := parseRetryAfter(tc.value, now); got != tc.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	wrap := func(code int) error {
+		return errors.Wrap(&APIError{StatusCode: code}, "failed to do something")
+	}
+
+	tests := map[string]struct {
+		err  error
+		is   func(error) bool
+		want bool
+	}{
+		"NotFound":          {err: wrap(404), is: IsNotFound, want: true},
+		"Unauthorized":      {err: wrap(401), is: IsUnauthorized, want: true},
+		"PaymentRequired":   {err: wrap(402), is: IsForbidden, want: true},
+		"Forbidden":         {err: wrap(403), is: IsForbidden, want: true},
+		"RateLimited":       {err: wrap(429), is: IsRateLimited, want: true},
+		"Conflict":          {err: wrap(409), is: IsConflict, want: true},
+		"BadRequest":        {err: wrap(400), is: IsValidation, want: true},
+		"Unprocessable":     {err: wrap(422), is: IsValidation, want: true},
+		"ServerError":       {err: wrap(500), is: IsValidation, want: false},
+		"NotAnAPIError":     {err: errors.New("status 404"), is: IsNotFound, want: false},
+		"Nil":               {err: nil, is: IsRateLimited, want: false},
+		"ConflictNotFound":  {err: wrap(409), is: IsNotFound, want: false},
+		"UnauthorizedValid": {err: wrap(401), is: IsValidation, want: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.is(tc.err); got != tc.want {
+				t.Errorf("got %t, want %t for %v", got, tc.want, tc.err)
+			}
+		})
+	}
+}
+
+func TestErrorCondition(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		reason xpv1.ConditionReason
+		ok     bool
+	}{
+		"Unauthorized": {err: &APIError{StatusCode: 401}, reason: ReasonUnauthorized, ok: true},
+		"Forbidden":    {err: &APIError{StatusCode: 402}, reason: ReasonForbidden, ok: true},
+		"Conflict":     {err: &APIError{StatusCode: 409}, reason: ReasonConflict, ok: true},
+		"Validation":   {err: &APIError{StatusCode: 422, Message: "invalid timezone"}, reason: ReasonInvalidParameters, ok: true},
+		"RateLimited":  {err: &APIError{StatusCode: 429}},
+		"ServerError":  {err: &APIError{StatusCode: 500}},
+		"NotAPIError":  {err: errors.New("boom")},
+		"Nil":          {err: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := ErrorCondition(tc.err)
+			if ok != tc.ok {
+				t.Fatalf("ErrorCondition(...): ok = %t, want %t", ok, tc.ok)
+			}
+			if !ok {
+				return
+			}
+			if got.Type != xpv1.TypeReady || got.Reason != tc.reason {
+				t.Errorf("ErrorCondition(...): got %s/%s, want %s/%s", got.Type, got.Reason, xpv1.TypeReady, tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons for events recorded in addition to those of the managed reconciler,
+// which already records the outcome of every create, update and delete.
+const (
+	ReasonImported event.Reason = "ImportedExternalResource"
+	ReasonDrifted  event.Reason = "ExternalResourceDrifted"
+)
+
+// EventDedupWindow is how long an event is suppressed after an identical
+// event was recorded for the same object.
+const EventDedupWindow = 5 * time.Minute
+
+// NewEventRecorder returns a Recorder that records Kubernetes events through
+// r, dropping events identical to one recorded for the same object within the
+// EventDedupWindow.
+func NewEventRecorder(r record.EventRecorder) event.Recorder {
+	return NewDedupRecorder(event.NewAPIRecorder(r), EventDedupWindow)
+}
+
+// NewDedupRecorder returns a Recorder that passes events to r unless an event
+// of the same type, reason and message was passed for the same object within
+// window. This keeps an API error that fails every reconcile from flooding
+// the event stream.
+func NewDedupRecorder(r event.Recorder, window time.Duration) event.Recorder {
+	return &dedupRecorder{
+		recorder: r,
+		seen:     &seenEvents{window: window, now: time.Now, last: map[eventKey]time.Time{}},
+	}
+}
+
+type dedupRecorder struct {
+	recorder event.Recorder
+	seen     *seenEvents
+}
+
+func (r *dedupRecorder) Event(obj runtime.Object, e event.Event) {
+	if r.seen.recent(obj, e) {
+		return
+	}
+	r.recorder.Event(obj, e)
+}
+
+// WithAnnotations returns a Recorder that shares the events seen by r.
+func (r *dedupRecorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	return &dedupRecorder{recorder: r.recorder.WithAnnotations(keysAndValues...), seen: r.seen}
+}
+
+type eventKey struct {
+	object  string
+	typ     event.Type
+	reason  event.Reason
+	message string
+}
+
+// seenEvents remembers when each event was last recorded.
+type seenEvents struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	last   map[eventKey]time.Time
+	pruned time.Time
+}
+
+// recent returns true if e was recorded for obj within the window, and
+// otherwise remembers that it is being recorded now.
+func (s *seenEvents) recent(obj runtime.Object, e event.Event) bool {
+	k := eventKey{typ: e.Type, reason: e.Reason, message: e.Message}
+	if o, ok := obj.(metav1.Object); ok {
+		k.object = fmt.Sprintf("%s/%s/%s", o.GetUID(), o.GetNamespace(), o.GetName())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if t, ok := s.last[k]; ok && now.Sub(t) < s.window {
+		return true
+	}
+	s.last[k] = now
+
+	// Forget expired events now and then, so that deleted objects and one
+	// off messages do not accumulate.
+	if now.Sub(s.pruned) >= s.window {
+		for k, t := range s.last {
+			if now.Sub(t) >= s.window {
+				delete(s.last, k)
+			}
+		}
+		s.pruned = now
+	}
+	return false
+}
+
+// WithEvents wraps an ExternalClient so that importing an existing external
+// resource and drift from the desired state are recorded as events on the
+// managed resource. Errors are not, since the managed reconciler already
+// records a warning for every failed call.
+func WithEvents(ec managed.ExternalClient, r event.Recorder) managed.ExternalClient {
+	return &eventingClient{ExternalClient: ec, record: r}
+}
+
+type eventingClient struct {
+	managed.ExternalClient
+	record event.Recorder
+}
+
+func (c *eventingClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	// A resource is observed for the first time before it has a Ready
+	// condition. If it exists then and was not created by us, it was
+	// imported.
+	first := mg.GetCondition(xpv1.TypeReady).Reason == ""
+
+	o, err := c.ExternalClient.Observe(ctx, mg)
+	switch {
+	case err != nil, !o.ResourceExists || meta.WasDeleted(mg):
+	case first && meta.GetExternalCreateSucceeded(mg).IsZero():
+		c.record.Event(mg, event.Normal(ReasonImported, fmt.Sprintf("Imported existing external resource %q", meta.GetExternalName(mg))))
+	case !o.ResourceUpToDate:
+		c.record.Event(mg, event.Normal(ReasonDrifted, "External resource differs from the desired state and will be updated"))
+	}
+	return o, err
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// recorder remembers the reasons of the events it was asked to record.
+type recorder struct {
+	reasons []event.Reason
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.reasons = append(r.reasons, e.Reason)
+}
+
+func (r *recorder) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
+func TestDedupRecorder(t *testing.T) {
+	rec := &recorder{}
+	now := time.Now()
+	r := NewDedupRecorder(rec, time.Minute).(*dedupRecorder)
+	r.seen.now = func() time.Time { return now }
+
+	a := &goalv1beta1.Goal{}
+	a.SetName("a")
+	b := &goalv1beta1.Goal{}
+	b.SetName("b")
+	failed := event.Warning("CannotObserveExternalResource", errors.New("boom"))
+
+	r.Event(a, failed)
+	r.Event(a, failed)
+	r.Event(b, failed)
+	r.Event(a, event.Warning("CannotObserveExternalResource", errors.New("other")))
+	r.WithAnnotations("key", "value").Event(a, failed)
+
+	now = now.Add(time.Minute)
+	r.Event(a, failed)
+
+	want := []event.Reason{
+		"CannotObserveExternalResource",
+		"CannotObserveExternalResource",
+		"CannotObserveExternalResource",
+		"CannotObserveExternalResource",
+	}
+	if diff := cmp.Diff(want, rec.reasons); diff != "" {
+		t.Errorf("recorded events -want, +got:\n%s", diff)
+	}
+}
+
+// stubClient is an ExternalClient that returns the same observation and
+// error for every call.
+type stubClient struct {
+	o   managed.ExternalObservation
+	err error
+}
+
+func (e *stubClient) Observe(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+	return e.o, e.err
+}
+
+func (e *stubClient) Create(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, e.err
+}
+
+func (e *stubClient) Update(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, e.err
+}
+
+func (e *stubClient) Delete(context.Context, resource.Managed) (managed.ExternalDelete, error) {
+	return managed.ExternalDelete{}, e.err
+}
+
+func (e *stubClient) Disconnect(context.Context) error {
+	return nil
+}
+
+func TestWithEvents(t *testing.T) {
+	unauthorized := &APIError{StatusCode: http.StatusUnauthorized, Message: "invalid API key"}
+
+	cases := map[string]struct {
+		mg     *goalv1beta1.Goal
+		ec     *stubClient
+		create bool
+		want   []event.Reason
+	}{
+		"Imported": {
+			mg:   &goalv1beta1.Goal{},
+			ec:   &stubClient{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+			want: []event.Reason{ReasonImported},
+		},
+		"Created": {
+			mg: func() *goalv1beta1.Goal {
+				mg := &goalv1beta1.Goal{}
+				meta.SetExternalCreateSucceeded(mg, time.Now())
+				return mg
+			}(),
+			ec: &stubClient{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"Drifted": {
+			mg: func() *goalv1beta1.Goal {
+				mg := &goalv1beta1.Goal{}
+				mg.SetConditions(xpv1.Available())
+				return mg
+			}(),
+			ec:   &stubClient{o: managed.ExternalObservation{ResourceExists: true}},
+			want: []event.Reason{ReasonDrifted},
+		},
+		"NotFound": {
+			mg: &goalv1beta1.Goal{},
+			ec: &stubClient{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		// The managed reconciler records failed calls, so they must not be
+		// recorded twice.
+		"ObserveUnauthorized": {
+			mg: &goalv1beta1.Goal{},
+			ec: &stubClient{err: unauthorized},
+		},
+		"CreateUnauthorized": {
+			mg:     &goalv1beta1.Goal{},
+			ec:     &stubClient{err: unauthorized},
+			create: true,
+		},
+		"Transient": {
+			mg:   &goalv1beta1.Goal{},
+			ec:   &stubClient{err: &APIError{StatusCode: http.StatusBadGateway}},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &recorder{}
+			ec := WithEvents(tc.ec, rec)
+
+			if tc.create {
+				_, _ = ec.Create(context.Background(), tc.mg)
+			} else {
+				_, _ = ec.Observe(context.Background(), tc.mg)
+			}
+
+			if diff := cmp.Diff(tc.want, rec.reasons); diff != "" {
+				t.Errorf("recorded events -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides an in-memory Plausible Sites API for controller tests.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/rossigee/provider-plausible/internal/clients"
+)
+
+// Plausible serves the shared link, custom property and guest endpoints of the
+// Plausible Sites API from memory. Like Plausible, creating a shared link or
+// inviting a guest that already exists returns the existing one unchanged,
+// while creating a custom property that already exists updates its
+// description.
+type Plausible struct {
+	mu sync.Mutex
+
+	// SharedLinks are the shared links of the site, by name.
+	SharedLinks map[string]clients.SharedLink
+
+	// CustomProperties are the custom properties of the site, by key.
+	CustomProperties map[string]clients.CustomProperty
+
+	// Guests are the guests of the site, by email. New guests are pending.
+	Guests map[string]clients.Guest
+
+	// Deleted records the name of each object that was deleted, in order.
+	Deleted []string
+}
+
+// NewClient returns a client for a test server backed by f. The server is
+// closed when the test ends.
+func (f *Plausible) NewClient(t *testing.T) *clients.Client {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return clients.NewClient(clients.Config{BaseURL: srv.URL, APIKey: "test-key"})
+}
+
+// ServeHTTP implements http.Handler.
+func (f *Plausible) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch path := strings.TrimPrefix(r.URL.Path, "/api/v1/sites/"); {
+	case path == "shared-links":
+		f.sharedLinks(w, r)
+	case path == "custom-props" || strings.HasPrefix(path, "custom-props/"):
+		f.customProperties(w, r, strings.TrimPrefix(path, "custom-props/"))
+	case path == "guests" || strings.HasPrefix(path, "guests/"):
+		f.guests(w, r, strings.TrimPrefix(path, "guests/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *Plausible) sharedLinks(w http.ResponseWriter, r *http.Request) {
+	if f.SharedLinks == nil {
+		f.SharedLinks = map[string]clients.SharedLink{}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		links := []clients.SharedLink{}
+		for _, l := range f.SharedLinks {
+			links = append(links, l)
+		}
+		list(w, "shared_links", links)
+	case http.MethodPut:
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		l, ok := f.SharedLinks[body["name"]]
+		if !ok {
+			l = clients.SharedLink{
+				Name:        body["name"],
+				URL:         "https://plausible.io/share/example.com?auth=" + body["name"],
+				HasPassword: body["password"] != "",
+			}
+			f.SharedLinks[l.Name] = l
+		}
+		_ = json.NewEncoder(w).Encode(l)
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if _, ok := f.SharedLinks[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(f.SharedLinks, name)
+		f.Deleted = append(f.Deleted, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *Plausible) customProperties(w http.ResponseWriter, r *http.Request, key string) {
+	if f.CustomProperties == nil {
+		f.CustomProperties = map[string]clients.CustomProperty{}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		props := []clients.CustomProperty{}
+		for _, p := range f.CustomProperties {
+			props = append(props, p)
+		}
+		list(w, "custom_properties", props)
+	case http.MethodPut:
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		p := clients.CustomProperty{Key: body["key"], Description: body["description"], IsEnabled: true}
+		f.CustomProperties[p.Key] = p
+		_ = json.NewEncoder(w).Encode(p)
+	case http.MethodDelete:
+		if _, ok := f.CustomProperties[key]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(f.CustomProperties, key)
+		f.Deleted = append(f.Deleted, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *Plausible) guests(w http.ResponseWriter, r *http.Request, email string) {
+	if f.Guests == nil {
+		f.Guests = map[string]clients.Guest{}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		guests := []clients.Guest{}
+		for _, g := range f.Guests {
+			guests = append(guests, g)
+		}
+		list(w, "guests", guests)
+	case http.MethodPut:
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		g, ok := f.Guests[body["email"]]
+		if !ok {
+			g = clients.Guest{Email: body["email"], Role: body["role"], Status: "pending", InvitedAt: "2025-01-01T00:00:00Z"}
+			f.Guests[g.Email] = g
+		}
+		_ = json.NewEncoder(w).Encode(g)
+	case http.MethodDelete:
+		if _, ok := f.Guests[email]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(f.Guests, email)
+		f.Deleted = append(f.Deleted, email)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// list writes a single page list response with items under key.
+func list(w http.ResponseWriter, key string, items interface{}) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{key: items, "meta": map[string]interface{}{}})
+}
+
+// Reconcile observes mg and then creates or updates it as the managed
+// reconciler would. Like the managed reconciler, it keeps the external name
+// set by Observe and Create, but not one set by Update.
+func Reconcile(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+	o, err := e.Observe(ctx, mg)
+	switch {
+	case err != nil:
+		return err
+	case !o.ResourceExists:
+		_, err = e.Create(ctx, mg)
+	case !o.ResourceUpToDate:
+		annotations := maps.Clone(mg.GetAnnotations())
+		_, err = e.Update(ctx, mg)
+		mg.SetAnnotations(annotations)
+	}
+	return err
+}
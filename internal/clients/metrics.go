@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Headers in which Plausible reports the request quota of an API key.
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+)
+
+// codeError is the code label of requests that got no response at all.
+const codeError = "error"
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "plausible_api_requests_total",
+		Help: "Requests made to the Plausible API, by response status code. Each retry is counted.",
+	}, []string{"route", "method", "provider_config", "code"})
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "plausible_api_request_duration_seconds",
+		Help:    "Time taken by requests to the Plausible API, excluding client-side rate limiting.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "provider_config"})
+
+	apiRateLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "plausible_api_rate_limit",
+		Help: "Request quota of the API key, as last reported by Plausible.",
+	}, []string{"provider_config"})
+
+	apiRateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "plausible_api_rate_limit_remaining",
+		Help: "Requests left in the current quota window of the API key, as last reported by Plausible.",
+	}, []string{"provider_config"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestDuration, apiRateLimit, apiRateLimitRemaining)
+}
+
+// observeRequest records a single request attempt that started at start. resp
+// is nil if the request failed without a response.
+func (c *Client) observeRequest(method, route string, start time.Time, resp *http.Response) {
+	pc := c.config.ProviderConfigKey
+	apiRequestDuration.WithLabelValues(route, method, pc).Observe(time.Since(start).Seconds())
+
+	if resp == nil {
+		apiRequests.WithLabelValues(route, method, pc, codeError).Inc()
+		return
+	}
+	apiRequests.WithLabelValues(route, method, pc, strconv.Itoa(resp.StatusCode)).Inc()
+
+	if v, err := strconv.ParseFloat(resp.Header.Get(headerRateLimitLimit), 64); err == nil {
+		apiRateLimit.WithLabelValues(pc).Set(v)
+	}
+	if v, err := strconv.ParseFloat(resp.Header.Get(headerRateLimitRemaining), 64); err == nil {
+		apiRateLimitRemaining.WithLabelValues(pc).Set(v)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// value returns the current value of a counter or gauge.
+func value(t *testing.T, m prometheus.Metric) float64 {
+	t.Helper()
+	d := &dto.Metric{}
+	if err := m.Write(d); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+	if d.Counter != nil {
+		return d.Counter.GetValue()
+	}
+	return d.Gauge.GetValue()
+}
+
+func TestDoRequestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerRateLimitLimit, "600")
+		w.Header().Set(headerRateLimitRemaining, "598")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	pc := ProviderConfigKey("ProviderConfig", "metrics", "default")
+	route := "/sites/:site_id"
+	requests := apiRequests.WithLabelValues(route, http.MethodGet, pc, "404")
+	before := value(t, requests)
+
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ProviderConfigKey: pc})
+	if _, err := client.GetSite(context.Background(), "example.com"); err != nil {
+		t.Fatalf("GetSite() unexpected error: %v", err)
+	}
+
+	if got := value(t, requests) - before; got != 1 {
+		t.Errorf("requests_total increased by %v, want 1", got)
+	}
+	if got := value(t, apiRateLimit.WithLabelValues(pc)); got != 600 {
+		t.Errorf("rate_limit = %v, want 600", got)
+	}
+	if got := value(t, apiRateLimitRemaining.WithLabelValues(pc)); got != 598 {
+		t.Errorf("rate_limit_remaining = %v, want 598", got)
+	}
+
+	d := &dto.Metric{}
+	if err := apiRequestDuration.WithLabelValues(route, http.MethodGet, pc).(prometheus.Histogram).Write(d); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+	if d.Histogram.GetSampleCount() == 0 {
+		t.Error("request_duration_seconds recorded no samples")
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -24,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 )
 
 func TestClient_GetSite(t *testing.T) {
@@ -75,15 +77,12 @@ func TestClient_GetSite(t *testing.T) {
 			}))
 			defer server.Close()
 
-			client := &Client{
-				config: Config{
-					BaseURL: server.URL,
-					APIKey:  "test-key",
-				},
-				httpClient: &http.Client{},
-			}
+			client := NewClient(Config{
+				BaseURL: server.URL,
+				APIKey:  "test-key",
+			})
 
-			site, err := client.GetSite(tt.siteID)
+			site, err := client.GetSite(context.Background(), tt.siteID)
 
 			if tt.expectedError && err == nil {
 				t.Error("Expected error but got none")
@@ -125,20 +124,17 @@ func TestClient_CreateSite_Simple(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := &Client{
-		config: Config{
-			BaseURL: server.URL,
-			APIKey:  "test-key",
-		},
-		httpClient: &http.Client{},
-	}
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		APIKey:  "test-key",
+	})
 
 	req := CreateSiteRequest{
 		Domain:   "test.example.com",
 		Timezone: "UTC",
 	}
 
-	site, err := client.CreateSite(req)
+	site, err := client.CreateSite(context.Background(), req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -172,15 +168,12 @@ func TestClient_UpdateSite_Simple(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := &Client{
-		config: Config{
-			BaseURL: server.URL,
-			APIKey:  "test-key",
-		},
-		httpClient: &http.Client{},
-	}
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		APIKey:  "test-key",
+	})
 
-	site, err := client.UpdateSite("old.example.com", "new.example.com")
+	site, err := client.UpdateSite(context.Background(), "old.example.com", UpdateSiteRequest{Domain: "new.example.com"})
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -208,15 +201,12 @@ func TestClient_DeleteSite_Simple(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := &Client{
-		config: Config{
-			BaseURL: server.URL,
-			APIKey:  "test-key",
-		},
-		httpClient: &http.Client{},
-	}
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		APIKey:  "test-key",
+	})
 
-	err := client.DeleteSite("example.com")
+	err := client.DeleteSite(context.Background(), "example.com")
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -236,12 +226,12 @@ func TestIsNotFound_Simple(t *testing.T) {
 		},
 		{
 			name:     "404 error",
-			err:      &simpleError{msg: "API request failed with status 404: Not Found"},
+			err:      &APIError{StatusCode: 404, Message: "Not Found"},
 			expected: true,
 		},
 		{
 			name:     "500 error",
-			err:      &simpleError{msg: "API request failed with status 500: Internal Server Error"},
+			err:      &APIError{StatusCode: 500, Message: "Internal Server Error"},
 			expected: false,
 		},
 		{
@@ -270,13 +260,13 @@ func (e *simpleError) Error() string {
 }
 
 func TestIsNotFound_Production(t *testing.T) {
-	// Test with actual error from production
-	err := &simpleError{msg: "API request failed with status 404: {\"error\":\"Site not found\"}"}
+	// Test with actual error from production, wrapped as the controllers do
+	err := errors.Wrap(&APIError{StatusCode: 404, Message: "Site not found", Method: "GET", Path: "/api/v1/sites/example.com"}, "failed to get site")
 	if !IsNotFound(err) {
 		t.Error("Expected IsNotFound to return true for 404 error")
 	}
 
-	// Test string contains logic
+	// The message still carries the status for humans reading logs
 	if !strings.Contains(err.Error(), "404") {
 		t.Error("Error message should contain '404'")
 	}
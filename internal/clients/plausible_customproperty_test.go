@@ -17,6 +17,7 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -110,7 +111,7 @@ func TestClient_CreateCustomProperty(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.CreateCustomProperty(tt.request)
+			result, err := client.CreateCustomProperty(context.Background(), tt.request)
 
 			if tt.expectedError {
 				if err == nil {
@@ -222,7 +223,7 @@ func TestClient_ListCustomProperties(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.ListCustomProperties(tt.siteDomain)
+			result, err := client.ListCustomProperties(context.Background(), tt.siteDomain)
 
 			if tt.expectedError {
 				if err == nil {
@@ -310,7 +311,7 @@ func TestClient_GetCustomProperty(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.GetCustomProperty(tt.siteDomain, tt.propertyKey)
+			result, err := client.GetCustomProperty(context.Background(), tt.siteDomain, tt.propertyKey)
 
 			if tt.expectedError {
 				if err == nil {
@@ -385,7 +386,7 @@ func TestClient_DeleteCustomProperty(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			err := client.DeleteCustomProperty(tt.siteDomain, tt.propertyKey)
+			err := client.DeleteCustomProperty(context.Background(), tt.siteDomain, tt.propertyKey)
 
 			if tt.expectedError {
 				if err == nil {
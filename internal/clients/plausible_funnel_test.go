@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_CreateFunnel(t *testing.T) {
+	req := FunnelRequest{
+		SiteDomain: "example.com",
+		Name:       "Checkout",
+		Steps:      []FunnelStep{{GoalID: "1"}, {GoalID: "2"}},
+	}
+	want := &Funnel{ID: "10", Name: "Checkout", Steps: req.Steps}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/sites/funnels" {
+			t.Errorf("Expected PUT /api/v1/sites/funnels, got %s %s", r.Method, r.URL.Path)
+		}
+		var got FunnelRequest
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if diff := cmp.Diff(req, got); diff != "" {
+			t.Errorf("CreateFunnel() request body (-want +got):\n%s", diff)
+		}
+		_ = json.NewEncoder(w).Encode(want)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	got, err := client.CreateFunnel(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateFunnel() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CreateFunnel() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_GetFunnel(t *testing.T) {
+	first := ListFunnelsResponse{Funnels: []Funnel{{ID: "10", Name: "Checkout"}}}
+	first.Meta.After = "next"
+	pages := map[string]ListFunnelsResponse{
+		"":     first,
+		"next": {Funnels: []Funnel{{ID: "11", Name: "Signup"}}},
+	}
+
+	lists := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("site_id") != "example.com" {
+			t.Errorf("Expected site_id example.com, got %q", r.URL.Query().Get("site_id"))
+		}
+		lists++
+		_ = json.NewEncoder(w).Encode(pages[r.URL.Query().Get("after")])
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	byID, err := client.GetFunnel(ctx, "example.com", "11")
+	if err != nil {
+		t.Fatalf("GetFunnel() unexpected error: %v", err)
+	}
+	if byID == nil || byID.Name != "Signup" {
+		t.Errorf("GetFunnel() = %+v, want funnel Signup", byID)
+	}
+
+	byName, err := client.GetFunnelByName(ctx, "example.com", "Checkout")
+	if err != nil {
+		t.Fatalf("GetFunnelByName() unexpected error: %v", err)
+	}
+	if byName == nil || byName.ID != "10" {
+		t.Errorf("GetFunnelByName() = %+v, want funnel 10", byName)
+	}
+
+	missing, err := client.GetFunnel(ctx, "example.com", "12")
+	if err != nil {
+		t.Fatalf("GetFunnel() unexpected error: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("GetFunnel() = %+v, want nil", missing)
+	}
+
+	// Both pages are fetched once and then served from the cache.
+	if lists != 2 {
+		t.Errorf("made %d list calls, want 2", lists)
+	}
+}
+
+func TestClient_UpdateAndDeleteFunnel(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Funnel{ID: "10", Name: "Checkout v2"})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	if _, err := client.UpdateFunnel(ctx, "10", FunnelRequest{SiteDomain: "example.com", Name: "Checkout v2"}); err != nil {
+		t.Fatalf("UpdateFunnel() unexpected error: %v", err)
+	}
+	if err := client.DeleteFunnel(ctx, "example.com", "10"); err != nil {
+		t.Fatalf("DeleteFunnel() unexpected error: %v", err)
+	}
+
+	want := []string{
+		"PUT /api/v1/sites/funnels/10",
+		"DELETE /api/v1/sites/funnels/10?site_id=example.com",
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("requests (-want +got):\n%s", diff)
+	}
+}
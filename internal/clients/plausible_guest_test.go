@@ -17,6 +17,7 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -116,7 +117,7 @@ func TestClient_CreateGuest(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.CreateGuest(tt.request)
+			result, err := client.CreateGuest(context.Background(), tt.request)
 
 			if tt.expectedError {
 				if err == nil {
@@ -234,7 +235,7 @@ func TestClient_ListGuests(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.ListGuests(tt.siteDomain)
+			result, err := client.ListGuests(context.Background(), tt.siteDomain)
 
 			if tt.expectedError {
 				if err == nil {
@@ -326,7 +327,7 @@ func TestClient_GetGuest(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.GetGuest(tt.siteDomain, tt.email)
+			result, err := client.GetGuest(context.Background(), tt.siteDomain, tt.email)
 
 			if tt.expectedError {
 				if err == nil {
@@ -401,7 +402,7 @@ func TestClient_DeleteGuest(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			err := client.DeleteGuest(tt.siteDomain, tt.email)
+			err := client.DeleteGuest(context.Background(), tt.siteDomain, tt.email)
 
 			if tt.expectedError {
 				if err == nil {
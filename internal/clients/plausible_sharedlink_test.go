@@ -17,6 +17,7 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -110,7 +111,7 @@ func TestClient_CreateSharedLink(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.CreateSharedLink(tt.request)
+			result, err := client.CreateSharedLink(context.Background(), tt.request)
 
 			if tt.expectedError {
 				if err == nil {
@@ -222,7 +223,7 @@ func TestClient_ListSharedLinks(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.ListSharedLinks(tt.siteDomain)
+			result, err := client.ListSharedLinks(context.Background(), tt.siteDomain)
 
 			if tt.expectedError {
 				if err == nil {
@@ -310,7 +311,7 @@ func TestClient_GetSharedLink(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.GetSharedLink(tt.siteDomain, tt.linkName)
+			result, err := client.GetSharedLink(context.Background(), tt.siteDomain, tt.linkName)
 
 			if tt.expectedError {
 				if err == nil {
@@ -385,7 +386,7 @@ func TestClient_DeleteSharedLink(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			err := client.DeleteSharedLink(tt.siteDomain, tt.linkName)
+			err := client.DeleteSharedLink(context.Background(), tt.siteDomain, tt.linkName)
 
 			if tt.expectedError {
 				if err == nil {
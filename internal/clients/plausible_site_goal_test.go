@@ -17,92 +17,79 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 )
 
 func TestClient_GetSiteByDomain(t *testing.T) {
+	sites := map[string]Site{
+		"1": {ID: "1", Domain: "example.com", Timezone: "UTC"},
+		"2": {ID: "2", Domain: "renamed.com", Timezone: "EST"},
+	}
+	// Plausible keeps serving a renamed site under its old domain for a while.
+	aliases := map[string]string{"example.com": "1", "renamed.com": "2", "old.com": "2"}
+
 	tests := []struct {
 		name          string
 		domain        string
-		responseCode  int
-		responseBody  interface{}
+		status        int
 		expectedSite  *Site
+		expectedPaths []string
 		expectedError bool
 	}{
 		{
-			name:         "site found by domain",
-			domain:       "example.com",
-			responseCode: http.StatusOK,
-			responseBody: map[string]interface{}{
-				"sites": []map[string]interface{}{
-					{
-						"domain":   "example.com",
-						"timezone": "UTC",
-					},
-					{
-						"domain":   "other.com",
-						"timezone": "EST",
-					},
-				},
-				"meta": map[string]interface{}{
-					"limit": 100,
-				},
-			},
-			expectedSite: &Site{
-				Domain:   "example.com",
-				Timezone: "UTC",
-			},
-			expectedError: false,
+			name:          "site found by domain",
+			domain:        "example.com",
+			expectedSite:  &Site{ID: "1", Domain: "example.com", Timezone: "UTC"},
+			expectedPaths: []string{"/api/v1/sites/example.com"},
 		},
 		{
-			name:         "site not found by domain",
-			domain:       "nonexistent.com",
-			responseCode: http.StatusOK,
-			responseBody: map[string]interface{}{
-				"sites": []map[string]interface{}{
-					{
-						"domain":   "other.com",
-						"timezone": "EST",
-					},
-				},
-				"meta": map[string]interface{}{
-					"limit": 100,
-				},
-			},
-			expectedSite:  nil,
-			expectedError: false,
+			name:          "site not found by domain",
+			domain:        "nonexistent.com",
+			expectedPaths: []string{"/api/v1/sites/nonexistent.com"},
+		},
+		{
+			name:          "lookup returns another site",
+			domain:        "old.com",
+			expectedPaths: []string{"/api/v1/sites/old.com", "/api/v1/sites"},
 		},
 		{
 			name:          "api error",
 			domain:        "test.com",
-			responseCode:  http.StatusUnauthorized,
-			responseBody:  "Unauthorized",
-			expectedSite:  nil,
+			status:        http.StatusUnauthorized,
 			expectedError: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				if r.Method != "GET" {
 					t.Errorf("Expected GET request, got %s", r.Method)
 				}
-				if r.URL.Path != "/api/v1/sites" {
-					t.Errorf("Expected path /api/v1/sites, got %s", r.URL.Path)
-				}
+				paths = append(paths, r.URL.Path)
 
-				w.WriteHeader(tt.responseCode)
-				if tt.responseCode >= 400 {
-					_, _ = w.Write([]byte(tt.responseBody.(string)))
-				} else {
-					_ = json.NewEncoder(w).Encode(tt.responseBody)
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				if r.URL.Path == "/api/v1/sites" {
+					_ = json.NewEncoder(w).Encode(ListSitesResponse{Sites: []Site{sites["1"], sites["2"]}})
+					return
 				}
+				id, ok := aliases[strings.TrimPrefix(r.URL.Path, "/api/v1/sites/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(sites[id])
 			}))
 			defer server.Close()
 
@@ -111,7 +98,7 @@ func TestClient_GetSiteByDomain(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.GetSiteByDomain(tt.domain)
+			result, err := client.GetSiteByDomain(context.Background(), tt.domain)
 
 			if tt.expectedError {
 				if err == nil {
@@ -128,10 +115,76 @@ func TestClient_GetSiteByDomain(t *testing.T) {
 			if diff := cmp.Diff(tt.expectedSite, result); diff != "" {
 				t.Errorf("GetSiteByDomain() mismatch (-want +got):\n%s", diff)
 			}
+			if diff := cmp.Diff(tt.expectedPaths, paths); diff != "" {
+				t.Errorf("GetSiteByDomain() requests (-want +got):\n%s", diff)
+			}
 		})
 	}
 }
 
+func TestClient_GetSiteByDomain_Index(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/v1/sites":
+			_ = json.NewEncoder(w).Encode(ListSitesResponse{Sites: []Site{{ID: "42", Domain: "example.com"}}})
+		case "/api/v1/sites/42":
+			_ = json.NewEncoder(w).Encode(Site{ID: "42", Domain: "example.com"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	key := ProviderConfigKey("ClusterProviderConfig", "", t.Name())
+	ctx := context.Background()
+
+	if _, err := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ProviderConfigKey: key}).ListSites(ctx); err != nil {
+		t.Fatalf("ListSites() unexpected error: %v", err)
+	}
+
+	// A client of the same ProviderConfig fetches the site by its known ID.
+	site, err := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ProviderConfigKey: key}).GetSiteByDomain(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("GetSiteByDomain() unexpected error: %v", err)
+	}
+	if site == nil || site.ID != "42" {
+		t.Errorf("GetSiteByDomain() = %+v, want site 42", site)
+	}
+	if diff := cmp.Diff([]string{"/api/v1/sites", "/api/v1/sites/42"}, paths); diff != "" {
+		t.Errorf("GetSiteByDomain() requests (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_DeleteSite_KeepsIndexOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(ListSitesResponse{Sites: []Site{{ID: "42", Domain: "example.com"}}})
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": "forbidden"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	if _, err := c.ListSites(ctx); err != nil {
+		t.Fatalf("ListSites() unexpected error: %v", err)
+	}
+	if err := c.DeleteSite(ctx, "42"); err == nil {
+		t.Fatal("DeleteSite() expected an error")
+	}
+
+	// The site was not deleted, so its ID is still known.
+	if id, ok := c.sites.lookup("example.com"); !ok || id != "42" {
+		t.Errorf("sites.lookup() = %q, %t, want %q, true", id, ok, "42")
+	}
+}
+
 func TestClient_ListSites(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -215,7 +268,7 @@ func TestClient_ListSites(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.ListSites()
+			result, err := client.ListSites(context.Background())
 
 			if tt.expectedError {
 				if err == nil {
@@ -284,7 +337,7 @@ func TestClient_ListSites_Pagination(t *testing.T) {
 		APIKey:  "test-key",
 	})
 
-	result, err := client.ListSites()
+	result, err := client.ListSites(context.Background())
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -402,7 +455,7 @@ func TestClient_ListGoals(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.ListGoals(tt.siteDomain)
+			result, err := client.ListGoals(context.Background(), tt.siteDomain)
 
 			if tt.expectedError {
 				if err == nil {
@@ -473,7 +526,7 @@ func TestClient_ListGoals_Pagination(t *testing.T) {
 		APIKey:  "test-key",
 	})
 
-	result, err := client.ListGoals("example.com")
+	result, err := client.ListGoals(context.Background(), "example.com")
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -578,7 +631,7 @@ func TestClient_GetGoal(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.GetGoal(tt.siteDomain, tt.goalID)
+			result, err := client.GetGoal(context.Background(), tt.siteDomain, tt.goalID)
 
 			if tt.expectedError {
 				if err == nil {
@@ -606,6 +659,7 @@ func TestClient_CreateGoal(t *testing.T) {
 		request       CreateGoalRequest
 		responseCode  int
 		responseBody  interface{}
+		expectedBody  map[string]interface{}
 		expectedGoal  *Goal
 		expectedError bool
 	}{
@@ -649,6 +703,70 @@ func TestClient_CreateGoal(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name:       "revenue goal with display name and custom props",
+			siteDomain: "example.com",
+			request: CreateGoalRequest{
+				GoalType:    "event",
+				EventName:   "Purchase",
+				DisplayName: "Purchase (EUR)",
+				Currency:    "EUR",
+				CustomProps: map[string]string{"plan": "pro"},
+			},
+			responseCode: http.StatusOK,
+			responseBody: map[string]interface{}{
+				"id":           "goal-789",
+				"goal_type":    "event",
+				"event_name":   "Purchase",
+				"display_name": "Purchase (EUR)",
+				"currency":     "EUR",
+				"custom_props": map[string]string{"plan": "pro"},
+			},
+			expectedBody: map[string]interface{}{
+				"site_id":      "example.com",
+				"goal_type":    "event",
+				"event_name":   "Purchase",
+				"display_name": "Purchase (EUR)",
+				"currency":     "EUR",
+				"custom_props": map[string]interface{}{"plan": "pro"},
+			},
+			expectedGoal: &Goal{
+				ID:          "goal-789",
+				GoalType:    "event",
+				EventName:   "Purchase",
+				DisplayName: "Purchase (EUR)",
+				Currency:    "EUR",
+				CustomProps: map[string]string{"plan": "pro"},
+			},
+		},
+		{
+			name:       "scroll depth goal",
+			siteDomain: "example.com",
+			request: CreateGoalRequest{
+				GoalType:        "page",
+				PagePath:        "/blog/**",
+				ScrollThreshold: 75,
+			},
+			responseCode: http.StatusOK,
+			responseBody: map[string]interface{}{
+				"id":               "goal-790",
+				"goal_type":        "page",
+				"page_path":        "/blog/**",
+				"scroll_threshold": 75,
+			},
+			expectedBody: map[string]interface{}{
+				"site_id":          "example.com",
+				"goal_type":        "page",
+				"page_path":        "/blog/**",
+				"scroll_threshold": float64(75),
+			},
+			expectedGoal: &Goal{
+				ID:              "goal-790",
+				GoalType:        "page",
+				PagePath:        "/blog/**",
+				ScrollThreshold: 75,
+			},
+		},
 		{
 			name:       "api error",
 			siteDomain: "nonexistent.com",
@@ -672,6 +790,13 @@ func TestClient_CreateGoal(t *testing.T) {
 				if r.URL.Path != "/api/v1/sites/goals" {
 					t.Errorf("Expected path /api/v1/sites/goals, got %s", r.URL.Path)
 				}
+				if tt.expectedBody != nil {
+					var body map[string]interface{}
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					if diff := cmp.Diff(tt.expectedBody, body); diff != "" {
+						t.Errorf("CreateGoal() request body (-want +got):\n%s", diff)
+					}
+				}
 
 				w.WriteHeader(tt.responseCode)
 				if tt.responseCode >= 400 {
@@ -687,7 +812,7 @@ func TestClient_CreateGoal(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.CreateGoal(tt.siteDomain, tt.request)
+			result, err := client.CreateGoal(context.Background(), tt.siteDomain, tt.request)
 
 			if tt.expectedError {
 				if err == nil {
@@ -758,7 +883,7 @@ func TestClient_DeleteGoal(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			err := client.DeleteGoal(tt.goalID)
+			err := client.DeleteGoal(context.Background(), tt.goalID)
 
 			if tt.expectedError {
 				if err == nil {
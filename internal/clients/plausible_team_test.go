@@ -17,6 +17,7 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -137,7 +138,7 @@ func TestClient_ListTeams(t *testing.T) {
 				APIKey:  "test-key",
 			})
 
-			result, err := client.ListTeams()
+			result, err := client.ListTeams(context.Background())
 
 			if tt.expectedError {
 				if err == nil {
@@ -208,7 +209,7 @@ func TestClient_ListTeams_Pagination(t *testing.T) {
 		APIKey:  "test-key",
 	})
 
-	result, err := client.ListTeams()
+	result, err := client.ListTeams(context.Background())
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
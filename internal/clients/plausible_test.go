@@ -17,7 +17,17 @@ limitations under the License.
 package clients
 
 import (
+	"context"
 	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	namespacedv1beta1 "github.com/rossigee/provider-plausible/apis/namespaced/v1beta1"
+	"github.com/rossigee/provider-plausible/apis/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestIsNotFound(t *testing.T) {
@@ -33,12 +43,17 @@ func TestIsNotFound(t *testing.T) {
 		},
 		{
 			name:     "404 error",
-			err:      &testError{msg: "API request failed with status 404: Not Found"},
+			err:      &APIError{StatusCode: 404, Message: "Not Found"},
 			expected: true,
 		},
 		{
-			name:     "other error",
-			err:      &testError{msg: "API request failed with status 500: Internal Server Error"},
+			name:     "other status",
+			err:      &APIError{StatusCode: 500, Message: "Internal Server Error"},
+			expected: false,
+		},
+		{
+			name:     "404 message without APIError",
+			err:      &testError{msg: "API request failed with status 404: Not Found"},
 			expected: false,
 		},
 	}
@@ -86,3 +101,127 @@ func TestNewClient(t *testing.T) {
 		t.Error("client.httpClient is nil")
 	}
 }
+
+func TestGetProviderConfig(t *testing.T) {
+	secretRef := func(namespace string) *xpv1.SecretKeySelector {
+		return &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: "plausible-credentials", Namespace: namespace},
+			Key:             "credentials",
+		}
+	}
+	spec := func(namespace string) v1beta1.ProviderConfigSpec {
+		return v1beta1.ProviderConfigSpec{
+			Credentials: v1beta1.ProviderCredentials{
+				Source:                    xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef(namespace)},
+			},
+		}
+	}
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *namespacedv1beta1.ProviderConfig:
+				if key.Namespace != "team-a" || key.Name != "tenant" {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				// A tenant pointing at another namespace's secret.
+				o.Spec = spec("crossplane-system")
+			case *v1beta1.ClusterProviderConfig:
+				if key.Namespace != "" || key.Name != "shared" {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				o.Spec = spec("crossplane-system")
+			case *v1beta1.ProviderConfig:
+				// The cluster scoped ProviderConfig every reference resolved
+				// to before namespaced ProviderConfigs existed.
+				if key.Namespace != "" || key.Name != "default" {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				o.Spec = spec("plausible")
+			}
+			obj.SetNamespace(key.Namespace)
+			obj.SetName(key.Name)
+			return nil
+		},
+	}
+
+	cases := map[string]struct {
+		namespace string
+		ref       xpv1.ProviderConfigReference
+		want      *v1beta1.ProviderConfigSpec
+		wantKey   string
+		wantErr   bool
+	}{
+		"NamespacedPinsSecretNamespace": {
+			namespace: "team-a",
+			ref:       xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "tenant"},
+			want:      func() *v1beta1.ProviderConfigSpec { s := spec("team-a"); return &s }(),
+			wantKey:   "ProviderConfig/team-a/tenant",
+		},
+		"NamespacedInOtherNamespace": {
+			namespace: "team-b",
+			ref:       xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "tenant"},
+			wantErr:   true,
+		},
+		"Cluster": {
+			namespace: "team-b",
+			ref:       xpv1.ProviderConfigReference{Kind: "ClusterProviderConfig", Name: "shared"},
+			want:      func() *v1beta1.ProviderConfigSpec { s := spec("crossplane-system"); return &s }(),
+			wantKey:   "ClusterProviderConfig//shared",
+		},
+		"ClusterMissing": {
+			namespace: "team-b",
+			ref:       xpv1.ProviderConfigReference{Kind: "ClusterProviderConfig", Name: "missing"},
+			wantErr:   true,
+		},
+		"LegacyByClusterProviderConfigKind": {
+			namespace: "team-b",
+			ref:       xpv1.ProviderConfigReference{Kind: "ClusterProviderConfig", Name: "default"},
+			want:      func() *v1beta1.ProviderConfigSpec { s := spec("plausible"); return &s }(),
+			wantKey:   "ProviderConfig//default",
+		},
+		"LegacyByProviderConfigKind": {
+			namespace: "team-b",
+			ref:       xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "default"},
+			want:      func() *v1beta1.ProviderConfigSpec { s := spec("plausible"); return &s }(),
+			wantKey:   "ProviderConfig//default",
+		},
+		"LegacyWithoutKind": {
+			namespace: "team-b",
+			ref:       xpv1.ProviderConfigReference{Name: "default"},
+			want:      func() *v1beta1.ProviderConfigSpec { s := spec("plausible"); return &s }(),
+			wantKey:   "ProviderConfig//default",
+		},
+		"UnknownKind": {
+			namespace: "team-a",
+			ref:       xpv1.ProviderConfigReference{Kind: "Unknown", Name: "default"},
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc, err := getProviderConfig(context.Background(), kube, tc.namespace, &tc.ref)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("getProviderConfig(...): expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getProviderConfig(...): unexpected error: %v", err)
+			}
+			got, err := EffectiveSpec(pc)
+			if err != nil {
+				t.Fatalf("EffectiveSpec(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("EffectiveSpec(...): -want, +got:\n%s", diff)
+			}
+			if key := providerConfigKey(pc); key != tc.wantKey {
+				t.Errorf("providerConfigKey(...): got %q, want %q", key, tc.wantKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errResolveReferences = "cannot resolve references"
+	errUpdateManaged     = "cannot update managed resource"
+)
+
+// NewSiteDomainReferenceResolver returns a ReferenceResolver for resources
+// that reference a Site by domain. Unlike the default resolver, it resolves a
+// site reference or selector on every reconcile rather than only while the
+// domain is unset, so the resource follows the Site when it is renamed.
+// forget must clear the resolved domain of a resource if it has a reference or
+// selector to resolve it from again, and may clear other resolved values, such
+// as goal IDs, that should be followed the same way. unselect must clear the
+// site reference that a selector resolved to, since a resource may not have
+// both; only the resolved domain is kept.
+func NewSiteDomainReferenceResolver(c client.Client, forget, unselect func(resource.Managed)) managed.ReferenceResolver {
+	return &siteDomainResolver{client: c, forget: forget, unselect: unselect}
+}
+
+type siteDomainResolver struct {
+	client   client.Client
+	forget   func(resource.Managed)
+	unselect func(resource.Managed)
+}
+
+func (r *siteDomainResolver) ResolveReferences(ctx context.Context, mg resource.Managed) error {
+	rr, ok := mg.(interface {
+		ResolveReferences(context.Context, client.Reader) error
+	})
+	if !ok {
+		return nil
+	}
+
+	existing := mg.DeepCopyObject()
+	r.forget(mg)
+	if err := rr.ResolveReferences(ctx, r.client); err != nil {
+		return errors.Wrap(err, errResolveReferences)
+	}
+	r.unselect(mg)
+
+	if cmp.Equal(existing, mg) {
+		return nil
+	}
+
+	return errors.Wrap(r.client.Update(ctx, mg), errUpdateManaged)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
+	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSiteDomainReferenceResolver(t *testing.T) {
+	forget := func(mg resource.Managed) {
+		if cr := mg.(*goalv1beta1.Goal); cr.Spec.ForProvider.SiteDomainRef != nil || cr.Spec.ForProvider.SiteDomainSelector != nil {
+			cr.Spec.ForProvider.SiteDomain = nil
+		}
+	}
+	unselect := func(mg resource.Managed) {
+		if cr := mg.(*goalv1beta1.Goal); cr.Spec.ForProvider.SiteDomainSelector != nil {
+			cr.Spec.ForProvider.SiteDomainRef = nil
+		}
+	}
+
+	tests := map[string]struct {
+		siteDomain string
+		ref        *xpv1.Reference
+		selector   *xpv1.Selector
+		want       string
+		wantRef    bool
+		wantUpdate bool
+	}{
+		"SiteRenamed": {
+			siteDomain: "old.example.com",
+			ref:        &xpv1.Reference{Name: "my-site"},
+			want:       "new.example.com",
+			wantRef:    true,
+			wantUpdate: true,
+		},
+		"SiteUnchanged": {
+			siteDomain: "new.example.com",
+			ref:        &xpv1.Reference{Name: "my-site"},
+			want:       "new.example.com",
+			wantRef:    true,
+		},
+		"SelectedSiteRenamed": {
+			siteDomain: "old.example.com",
+			selector:   &xpv1.Selector{MatchLabels: map[string]string{"site": "main"}},
+			want:       "new.example.com",
+			wantUpdate: true,
+		},
+		"SelectedSiteUnchanged": {
+			siteDomain: "new.example.com",
+			selector:   &xpv1.Selector{MatchLabels: map[string]string{"site": "main"}},
+			want:       "new.example.com",
+		},
+		"ReferenceReplacedBySelector": {
+			siteDomain: "new.example.com",
+			ref:        &xpv1.Reference{Name: "my-site"},
+			selector:   &xpv1.Selector{MatchLabels: map[string]string{"site": "main"}},
+			want:       "new.example.com",
+			wantUpdate: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			site := sitev1beta1.Site{}
+			site.SetName("my-site")
+			site.Status.AtProvider.Domain = "new.example.com"
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					site.DeepCopyInto(obj.(*sitev1beta1.Site))
+					return nil
+				},
+				MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					list.(*sitev1beta1.SiteList).Items = []sitev1beta1.Site{site}
+					return nil
+				},
+				MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					return nil
+				},
+			}
+
+			cr := &goalv1beta1.Goal{}
+			cr.SetNamespace("default")
+			cr.Spec.ForProvider.SiteDomain = &tc.siteDomain
+			cr.Spec.ForProvider.SiteDomainRef = tc.ref
+			cr.Spec.ForProvider.SiteDomainSelector = tc.selector
+
+			r := NewSiteDomainReferenceResolver(kube, forget, unselect)
+			if err := r.ResolveReferences(context.Background(), cr); err != nil {
+				t.Fatalf("ResolveReferences() unexpected error: %v", err)
+			}
+
+			if got := cr.Spec.ForProvider.SiteDomain; got == nil || *got != tc.want {
+				t.Errorf("ResolveReferences() siteDomain = %v, want %q", got, tc.want)
+			}
+			if got := cr.Spec.ForProvider.SiteDomainRef != nil; got != tc.wantRef {
+				t.Errorf("ResolveReferences() has siteDomainRef = %t, want %t", got, tc.wantRef)
+			}
+			if updated != tc.wantUpdate {
+				t.Errorf("ResolveReferences() updated = %t, want %t", updated, tc.wantUpdate)
+			}
+		})
+	}
+}
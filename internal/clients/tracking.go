@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Tracking describes how a web page reports to Plausible for a site.
+type Tracking struct {
+	// ScriptURL is the tracker script, including the extensions selected by
+	// the site's tracker script configuration.
+	ScriptURL string
+
+	// EventEndpoint is where the tracker script sends events.
+	EventEndpoint string
+
+	// Snippet is the HTML to place in the <head> of every page of the site.
+	Snippet string
+}
+
+// Tracking renders the tracker script URL, event endpoint and snippet of a
+// site served by the Plausible instance this client talks to.
+func (c *Client) Tracking(site *Site) Tracking {
+	base := strings.TrimRight(c.config.BaseURL, "/")
+
+	name := strings.Join(append([]string{"script"}, scriptExtensions(site.TrackerScriptConfiguration)...), ".")
+	t := Tracking{
+		ScriptURL:     fmt.Sprintf("%s/js/%s.js", base, name),
+		EventEndpoint: base + "/api/event",
+	}
+
+	t.Snippet = fmt.Sprintf(`<script defer data-domain="%s" data-api="%s" src="%s"></script>`,
+		html.EscapeString(site.Domain), html.EscapeString(t.EventEndpoint), html.EscapeString(t.ScriptURL))
+	if tc := site.TrackerScriptConfiguration; tc != nil && isSet(tc.Track404Pages) {
+		// 404 pages report through the plausible() queue, which must exist
+		// before the deferred script has loaded.
+		t.Snippet += "\n" + `<script>window.plausible = window.plausible || function() { (window.plausible.q = window.plausible.q || []).push(arguments) }</script>`
+	}
+
+	return t
+}
+
+// scriptExtensions returns the tracker script extensions enabled by tc, in
+// the order Plausible documents them.
+func scriptExtensions(tc *TrackerScriptConfiguration) []string {
+	if tc == nil {
+		return nil
+	}
+
+	var ext []string
+	for _, e := range []struct {
+		name    string
+		enabled *bool
+	}{
+		{"hash", tc.HashBasedRouting},
+		{"outbound-links", tc.OutboundLinks},
+		{"file-downloads", tc.FileDownloads},
+		{"tagged-events", tc.TaggedEvents},
+		{"revenue", tc.RevenueTracking},
+		{"pageview-props", tc.PageviewProps},
+	} {
+		if isSet(e.enabled) {
+			ext = append(ext, e.name)
+		}
+	}
+	return ext
+}
+
+func isSet(b *bool) bool {
+	return b != nil && *b
+}
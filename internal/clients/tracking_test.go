@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTracking(t *testing.T) {
+	yes, no := true, false
+
+	tests := map[string]struct {
+		baseURL string
+		site    *Site
+		want    Tracking
+	}{
+		"Plain": {
+			baseURL: "https://plausible.io",
+			site:    &Site{Domain: "example.com"},
+			want: Tracking{
+				ScriptURL:     "https://plausible.io/js/script.js",
+				EventEndpoint: "https://plausible.io/api/event",
+				Snippet:       `<script defer data-domain="example.com" data-api="https://plausible.io/api/event" src="https://plausible.io/js/script.js"></script>`,
+			},
+		},
+		"SelfHostedWithExtensions": {
+			baseURL: "https://analytics.example.org/",
+			site: &Site{
+				Domain: "example.com",
+				TrackerScriptConfiguration: &TrackerScriptConfiguration{
+					OutboundLinks:    &yes,
+					FileDownloads:    &no,
+					HashBasedRouting: &yes,
+					Track404Pages:    &yes,
+				},
+			},
+			want: Tracking{
+				ScriptURL:     "https://analytics.example.org/js/script.hash.outbound-links.js",
+				EventEndpoint: "https://analytics.example.org/api/event",
+				Snippet: `<script defer data-domain="example.com" data-api="https://analytics.example.org/api/event" src="https://analytics.example.org/js/script.hash.outbound-links.js"></script>` + "\n" +
+					`<script>window.plausible = window.plausible || function() { (window.plausible.q = window.plausible.q || []).push(arguments) }</script>`,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := NewClient(Config{BaseURL: tc.baseURL})
+			if diff := cmp.Diff(tc.want, c.Tracking(tc.site)); diff != "" {
+				t.Errorf("Tracking() -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rossigee/provider-plausible/apis/v1beta1"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// Transport defaults, used when a ProviderConfig does not override them.
+const (
+	defaultTimeout           = 30 * time.Second
+	defaultMaxRetries        = 3
+	defaultMinBackoff        = 1 * time.Second
+	defaultMaxBackoff        = 30 * time.Second
+	defaultRequestsPerMinute = 10
+	defaultBurst             = 10
+)
+
+// ProviderConfigKey identifies a ProviderConfig or ClusterProviderConfig.
+// Clients created with the same key share a single token bucket, so the rate
+// limit applies to all resources using that configuration.
+func ProviderConfigKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// applyTransport copies the transport settings of a ProviderConfig spec onto
+// cfg, filling in defaults for anything left unset.
+func applyTransport(cfg *Config, t *v1beta1.TransportConfig) {
+	cfg.Timeout = defaultTimeout
+	cfg.MaxRetries = defaultMaxRetries
+	cfg.MinBackoff = defaultMinBackoff
+	cfg.MaxBackoff = defaultMaxBackoff
+	cfg.RequestsPerMinute = defaultRequestsPerMinute
+	cfg.Burst = defaultBurst
+
+	if t == nil {
+		return
+	}
+	if t.Timeout != nil {
+		cfg.Timeout = t.Timeout.Duration
+	}
+	if t.MaxRetries != nil {
+		cfg.MaxRetries = *t.MaxRetries
+	}
+	if t.MinBackoff != nil {
+		cfg.MinBackoff = t.MinBackoff.Duration
+	}
+	if t.MaxBackoff != nil {
+		cfg.MaxBackoff = t.MaxBackoff.Duration
+	}
+	if t.RequestsPerMinute != nil {
+		cfg.RequestsPerMinute = *t.RequestsPerMinute
+	}
+	if t.Burst != nil {
+		cfg.Burst = *t.Burst
+	}
+}
+
+// limiters holds the token bucket of every ProviderConfig seen so far.
+var limiters = &limiterRegistry{buckets: map[string]bucket{}}
+
+type bucket struct {
+	rpm     int
+	burst   int
+	limiter flowcontrol.RateLimiter
+}
+
+type limiterRegistry struct {
+	mu      sync.Mutex
+	buckets map[string]bucket
+}
+
+// get returns the limiter for key, replacing it if the configured rate has
+// changed since it was created. A zero rate disables client-side limiting.
+func (r *limiterRegistry) get(key string, rpm, burst int) flowcontrol.RateLimiter {
+	if rpm <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	newLimiter := func() flowcontrol.RateLimiter {
+		return flowcontrol.NewTokenBucketRateLimiter(float32(rpm)/60, burst)
+	}
+	if key == "" {
+		return newLimiter()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.buckets[key]; ok && b.rpm == rpm && b.burst == burst {
+		return b.limiter
+	}
+	b := bucket{rpm: rpm, burst: burst, limiter: newLimiter()}
+	r.buckets[key] = b
+	return b.limiter
+}
+
+// isIdempotent returns true if a request with the given method can safely be
+// sent more than once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryDelay decides whether a response should be retried, and if so how
+// long to wait first. Plausible's Retry-After is honoured when present; a
+// Retry-After longer than MaxBackoff is not retried, so that a worker is not
+// blocked for the length of a quota window.
+func (c *Client) retryDelay(method string, resp *http.Response, attempt int) (time.Duration, bool) {
+	if attempt >= c.config.MaxRetries || !isIdempotent(method) {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return 0, false
+	}
+
+	if ra := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ra > 0 {
+		if ra > c.config.MaxBackoff {
+			return 0, false
+		}
+		return ra, true
+	}
+
+	return backoff(attempt, c.config.MinBackoff, c.config.MaxBackoff), true
+}
+
+// backoff returns an exponential delay for the given attempt, capped at max,
+// with jitter so that concurrent reconciles do not retry in lockstep.
+func backoff(attempt int, minDelay, maxDelay time.Duration) time.Duration {
+	d := minDelay << attempt
+	if d <= 0 || d > maxDelay {
+		d = maxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// sleep waits for d, returning early with an error if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// discard drains and closes a response body so the connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
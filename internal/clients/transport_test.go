@@ -0,0 +1,303 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-plausible/apis/v1beta1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDoRequestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int
+		retryAfter string
+		wantCalls  int
+		wantSleeps []time.Duration
+		wantStatus int
+	}{
+		{
+			name:       "honours retry-after on 429",
+			method:     http.MethodGet,
+			statuses:   []int{429, 429, 200},
+			retryAfter: "2",
+			wantCalls:  3,
+			wantSleeps: []time.Duration{2 * time.Second, 2 * time.Second},
+			wantStatus: 200,
+		},
+		{
+			name:       "gives up after max retries",
+			method:     http.MethodDelete,
+			statuses:   []int{503, 503, 503, 503, 503},
+			wantCalls:  4,
+			wantStatus: 503,
+		},
+		{
+			name:       "does not retry non-idempotent requests",
+			method:     http.MethodPost,
+			statuses:   []int{503, 200},
+			wantCalls:  1,
+			wantStatus: 503,
+		},
+		{
+			name:       "does not retry client errors",
+			method:     http.MethodGet,
+			statuses:   []int{404, 200},
+			wantCalls:  1,
+			wantStatus: 404,
+		},
+		{
+			name:       "does not block on a long retry-after",
+			method:     http.MethodGet,
+			statuses:   []int{429, 200},
+			retryAfter: "3600",
+			wantCalls:  1,
+			wantStatus: 429,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[calls]
+				calls++
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{
+				BaseURL:    server.URL,
+				APIKey:     "test-key",
+				MaxRetries: 3,
+				MinBackoff: time.Second,
+				MaxBackoff: 30 * time.Second,
+			})
+			var sleeps []time.Duration
+			client.sleep = func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			resp, err := client.doRequest(context.Background(), tt.method, "/sites", "/sites", nil)
+			if err != nil {
+				t.Fatalf("doRequest() unexpected error: %v", err)
+			}
+			discard(resp)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("doRequest() status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("doRequest() made %d calls, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantSleeps != nil {
+				if diff := cmp.Diff(tt.wantSleeps, sleeps); diff != "" {
+					t.Errorf("doRequest() sleeps -want, +got:\n%s", diff)
+				}
+			}
+			if len(sleeps) != tt.wantCalls-1 {
+				t.Errorf("doRequest() slept %d times, want %d", len(sleeps), tt.wantCalls-1)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	minDelay, maxDelay := time.Second, 10*time.Second
+	for attempt, ceiling := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		for i := 0; i < 50; i++ {
+			d := backoff(attempt, minDelay, maxDelay)
+			if d < ceiling/2 || d > ceiling {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", attempt, d, ceiling/2, ceiling)
+			}
+		}
+	}
+}
+
+func TestLimiterRegistry(t *testing.T) {
+	r := &limiterRegistry{buckets: map[string]bucket{}}
+
+	a := r.get("ProviderConfig/team-a/default", 60, 5)
+	if a == nil {
+		t.Fatal("get() returned nil limiter")
+	}
+	if b := r.get("ProviderConfig/team-a/default", 60, 5); b != a {
+		t.Error("get() with the same key and rate should share a limiter")
+	}
+	if b := r.get("ProviderConfig/team-b/default", 60, 5); b == a {
+		t.Error("get() with a different key should not share a limiter")
+	}
+	if b := r.get("ProviderConfig/team-a/default", 120, 5); b == a {
+		t.Error("get() with a changed rate should replace the limiter")
+	}
+	if b := r.get("ProviderConfig/team-a/default", 0, 5); b != nil {
+		t.Error("get() with a zero rate should disable limiting")
+	}
+}
+
+func TestApplyTransport(t *testing.T) {
+	retries, rpm := 0, 30
+
+	tests := map[string]struct {
+		transport *v1beta1.TransportConfig
+		want      Config
+	}{
+		"Defaults": {
+			want: Config{
+				Timeout:           defaultTimeout,
+				MaxRetries:        defaultMaxRetries,
+				MinBackoff:        defaultMinBackoff,
+				MaxBackoff:        defaultMaxBackoff,
+				RequestsPerMinute: defaultRequestsPerMinute,
+				Burst:             defaultBurst,
+			},
+		},
+		"Overrides": {
+			transport: &v1beta1.TransportConfig{
+				Timeout:           &metav1.Duration{Duration: 5 * time.Second},
+				MaxRetries:        &retries,
+				MaxBackoff:        &metav1.Duration{Duration: time.Minute},
+				RequestsPerMinute: &rpm,
+			},
+			want: Config{
+				Timeout:           5 * time.Second,
+				MaxRetries:        0,
+				MinBackoff:        defaultMinBackoff,
+				MaxBackoff:        time.Minute,
+				RequestsPerMinute: 30,
+				Burst:             defaultBurst,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Config{}
+			applyTransport(&got, tc.transport)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("applyTransport() -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDoRequestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(prev)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "site.observe")
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	if _, err := client.GetSite(ctx, "example.com"); err != nil {
+		t.Fatalf("GetSite() unexpected error: %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "plausible GET /sites/:site_id" {
+		t.Errorf("span name = %q, want %q", span.Name(), "plausible GET /sites/:site_id")
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("request span is not a child of the caller's span")
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want %v", span.Status().Code, codes.Error)
+	}
+
+	want := map[attribute.Key]attribute.Value{
+		"http.method":      attribute.StringValue("GET"),
+		"http.route":       attribute.StringValue("/sites/:site_id"),
+		"http.status_code": attribute.IntValue(404),
+	}
+	for _, kv := range span.Attributes() {
+		if v, ok := want[kv.Key]; ok {
+			if v != kv.Value {
+				t.Errorf("attribute %s = %v, want %v", kv.Key, kv.Value.Emit(), v.Emit())
+			}
+			delete(want, kv.Key)
+		}
+	}
+	for k := range want {
+		t.Errorf("missing attribute %s", k)
+	}
+
+	wantParent := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+	if traceparent != wantParent {
+		t.Errorf("traceparent header = %q, want %q", traceparent, wantParent)
+	}
+}
+
+func TestDoRequestContextCanceled(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		BaseURL:    server.URL,
+		APIKey:     "test-key",
+		MaxRetries: 3,
+		MinBackoff: time.Hour,
+		MaxBackoff: time.Hour,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.doRequest(ctx, http.MethodGet, "/sites", "/sites", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("doRequest() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if calls != 1 {
+		t.Errorf("doRequest() made %d calls, want 1", calls)
+	}
+}
@@ -18,9 +18,15 @@ package controller
 
 import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/rossigee/provider-plausible/internal/controller/customproperty"
+	"github.com/rossigee/provider-plausible/internal/controller/funnel"
 	"github.com/rossigee/provider-plausible/internal/controller/goal"
+	"github.com/rossigee/provider-plausible/internal/controller/guest"
 	"github.com/rossigee/provider-plausible/internal/controller/providerconfig"
+	"github.com/rossigee/provider-plausible/internal/controller/sharedlink"
 	"github.com/rossigee/provider-plausible/internal/controller/site"
+	"github.com/rossigee/provider-plausible/internal/controller/team"
+	"github.com/rossigee/provider-plausible/internal/features"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -36,5 +42,22 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	if err := goal.Setup(mgr, o); err != nil {
 		return err
 	}
+	if err := sharedlink.Setup(mgr, o); err != nil {
+		return err
+	}
+	if err := customproperty.Setup(mgr, o); err != nil {
+		return err
+	}
+	if err := guest.Setup(mgr, o); err != nil {
+		return err
+	}
+	if err := team.Setup(mgr, o); err != nil {
+		return err
+	}
+	if o.Features.Enabled(features.EnableAlphaFunnels) {
+		if err := funnel.Setup(mgr, o); err != nil {
+			return err
+		}
+	}
 	return nil
 }
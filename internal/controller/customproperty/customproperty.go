@@ -0,0 +1,314 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customproperty
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	custompropertyv1beta1 "github.com/rossigee/provider-plausible/apis/customproperty/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/tracing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotCustomProperty = "managed resource is not a CustomProperty custom resource"
+
+	errNoSiteDomain  = "no site domain specified"
+	errDeleteRenamed = "failed to delete custom property with the previous key"
+)
+
+// Setup adds a controller that reconciles CustomProperty managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(custompropertyv1beta1.CustomPropertyGroupKind.String())
+	recorder := clients.NewEventRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(custompropertyv1beta1.CustomPropertyGroupVersionKind),
+		managed.WithExternalConnector(&connector{
+			kube:         mgr.GetClient(),
+			usage:        clients.NewProviderConfigUsageTracker(mgr.GetClient()),
+			newServiceFn: clients.NewClient,
+			recorder:     recorder,
+		}),
+		// The external name is the key of the property in Plausible, which is
+		// set in the spec, so it must not default to the name of the
+		// Kubernetes object.
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithReferenceResolver(clients.NewSiteDomainReferenceResolver(mgr.GetClient(), forgetSiteDomain, unselectSite)))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&custompropertyv1beta1.CustomProperty{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// forgetSiteDomain clears the site domain of a CustomProperty that references or selects its Site,
+// so that it is resolved again and follows the Site if it is renamed.
+func forgetSiteDomain(mg resource.Managed) {
+	if cr, ok := mg.(*custompropertyv1beta1.CustomProperty); ok && (cr.Spec.ForProvider.SiteDomainRef != nil || cr.Spec.ForProvider.SiteDomainSelector != nil) {
+		cr.Spec.ForProvider.SiteDomain = nil
+	}
+}
+
+// unselectSite clears the Site reference a siteDomainSelector resolved to, so
+// that the CustomProperty keeps only the selector and is validated as it was applied.
+func unselectSite(mg resource.Managed) {
+	if cr, ok := mg.(*custompropertyv1beta1.CustomProperty); ok && cr.Spec.ForProvider.SiteDomainSelector != nil {
+		cr.Spec.ForProvider.SiteDomainRef = nil
+	}
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(config clients.Config) *clients.Client
+	recorder     event.Recorder
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*custompropertyv1beta1.CustomProperty)
+	if !ok {
+		return nil, errors.New(errNotCustomProperty)
+	}
+
+	cfg, err := clients.GetConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := c.newServiceFn(*cfg)
+
+	return clients.WithEvents(clients.WithErrorConditions(&external{service: svc, kube: c.kube}), c.recorder), nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	service *clients.Client
+	kube    client.Client
+}
+
+// getSiteDomain returns the domain of the site the custom property belongs to. Any
+// siteDomainRef or siteDomainSelector has already been resolved into
+// siteDomain by the time the managed reconciler calls the external client.
+func getSiteDomain(cr *custompropertyv1beta1.CustomProperty) (string, error) {
+	if cr.Spec.ForProvider.SiteDomain == nil || *cr.Spec.ForProvider.SiteDomain == "" {
+		return "", errors.New(errNoSiteDomain)
+	}
+	return *cr.Spec.ForProvider.SiteDomain, nil
+}
+
+// propertyKey returns the key of the custom property that was last created or
+// adopted for cr, or the key from the spec if there is none yet.
+func propertyKey(cr *custompropertyv1beta1.CustomProperty) string {
+	if en := meta.GetExternalName(cr); en != "" {
+		return en
+	}
+	return cr.Spec.ForProvider.Key
+}
+
+// rekeyed returns true if the key of the custom property changed in the spec
+// since it was created. A changed key is a different property, so the
+// property is replaced unless cr is being deleted.
+func rekeyed(cr *custompropertyv1beta1.CustomProperty) bool {
+	return cr.GetDeletionTimestamp() == nil && propertyKey(cr) != cr.Spec.ForProvider.Key
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*custompropertyv1beta1.CustomProperty)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotCustomProperty)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "customproperty.observe", "CustomProperty", cr.GetName(), "observe")
+	defer span.End()
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	if rekeyed(cr) {
+		// Report the property as missing so that Create replaces it. Unlike
+		// Update, Create can change the external name.
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+
+	prop, err := c.service.GetCustomProperty(ctx, siteDomain, propertyKey(cr))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to get custom property")
+	}
+
+	if prop == nil {
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+
+	meta.SetExternalName(cr, prop.Key)
+
+	cr.Status.AtProvider = custompropertyv1beta1.CustomPropertyObservation{
+		Key:         prop.Key,
+		Description: prop.Description,
+		IsEnabled:   prop.IsEnabled,
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: c.isUpToDate(cr, prop),
+	}, nil
+}
+
+func (c *external) isUpToDate(cr *custompropertyv1beta1.CustomProperty, prop *clients.CustomProperty) bool {
+	// An unset description leaves whatever Plausible has alone
+	if cr.Spec.ForProvider.Description != nil && *cr.Spec.ForProvider.Description != prop.Description {
+		return false
+	}
+
+	return true
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*custompropertyv1beta1.CustomProperty)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotCustomProperty)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "customproperty.create", "CustomProperty", cr.GetName(), "create")
+	defer span.End()
+
+	cr.SetConditions(xpv1.Creating())
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	// Observe reports a property whose key changed in the spec as missing,
+	// so it is replaced here.
+	var replaced string
+	if rekeyed(cr) {
+		replaced = propertyKey(cr)
+	}
+
+	if err := c.put(ctx, siteDomain, cr); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, "failed to create custom property")
+	}
+
+	if replaced != "" {
+		err := c.service.DeleteCustomProperty(ctx, siteDomain, replaced)
+		if err != nil && !clients.IsNotFound(err) {
+			return managed.ExternalCreation{}, errors.Wrap(err, errDeleteRenamed)
+		}
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+// put creates the custom property, or updates its description if it already
+// exists, since the Plausible endpoint behaves as an upsert.
+func (c *external) put(ctx context.Context, siteDomain string, cr *custompropertyv1beta1.CustomProperty) error {
+	req := clients.CreateCustomPropertyRequest{
+		SiteDomain: siteDomain,
+		Key:        cr.Spec.ForProvider.Key,
+	}
+
+	if cr.Spec.ForProvider.Description != nil {
+		req.Description = *cr.Spec.ForProvider.Description
+	}
+
+	prop, err := c.service.CreateCustomProperty(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	meta.SetExternalName(cr, prop.Key)
+
+	return nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*custompropertyv1beta1.CustomProperty)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotCustomProperty)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "customproperty.update", "CustomProperty", cr.GetName(), "update")
+	defer span.End()
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if err := c.put(ctx, siteDomain, cr); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to update custom property")
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*custompropertyv1beta1.CustomProperty)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotCustomProperty)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "customproperty.delete", "CustomProperty", cr.GetName(), "delete")
+	defer span.End()
+
+	cr.SetConditions(xpv1.Deleting())
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+
+	err = c.service.DeleteCustomProperty(ctx, siteDomain, propertyKey(cr))
+	if err != nil && !clients.IsNotFound(err) {
+		return managed.ExternalDelete{}, errors.Wrap(err, "failed to delete custom property")
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	// Nothing to disconnect for Plausible API client
+	return nil
+}
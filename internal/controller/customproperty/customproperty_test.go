@@ -0,0 +1,265 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customproperty
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	custompropertyv1beta1 "github.com/rossigee/provider-plausible/apis/customproperty/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/clients/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newExternal(t *testing.T, f *fake.Plausible) *external {
+	t.Helper()
+	return &external{service: f.NewClient(t)}
+}
+
+func customProperty(key string, description *string, externalName string) *custompropertyv1beta1.CustomProperty {
+	cr := &custompropertyv1beta1.CustomProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "author"},
+		Spec: custompropertyv1beta1.CustomPropertySpec{
+			ForProvider: custompropertyv1beta1.CustomPropertyParameters{
+				SiteDomain:  stringPtr("example.com"),
+				Key:         key,
+				Description: description,
+			},
+		},
+	}
+	if externalName != "" {
+		meta.SetExternalName(cr, externalName)
+	}
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	deleting := customProperty("writer", nil, "plan")
+	deleting.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	cases := map[string]struct {
+		props map[string]clients.CustomProperty
+		cr    *custompropertyv1beta1.CustomProperty
+		want  managed.ExternalObservation
+		obs   custompropertyv1beta1.CustomPropertyObservation
+	}{
+		"NotFound": {
+			props: map[string]clients.CustomProperty{},
+			cr:    customProperty("author", nil, ""),
+			want:  managed.ExternalObservation{ResourceExists: false},
+		},
+		"UpToDate": {
+			props: map[string]clients.CustomProperty{
+				"author": {Key: "author", Description: "Post author", IsEnabled: true},
+			},
+			cr:   customProperty("author", stringPtr("Post author"), ""),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			obs:  custompropertyv1beta1.CustomPropertyObservation{Key: "author", Description: "Post author", IsEnabled: true},
+		},
+		"UnsetDescriptionIgnored": {
+			props: map[string]clients.CustomProperty{
+				"author": {Key: "author", Description: "Post author", IsEnabled: true},
+			},
+			cr:   customProperty("author", nil, "author"),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			obs:  custompropertyv1beta1.CustomPropertyObservation{Key: "author", Description: "Post author", IsEnabled: true},
+		},
+		"DescriptionDrift": {
+			props: map[string]clients.CustomProperty{
+				"author": {Key: "author", Description: "old", IsEnabled: true},
+			},
+			cr:   customProperty("author", stringPtr("new"), "author"),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			obs:  custompropertyv1beta1.CustomPropertyObservation{Key: "author", Description: "old", IsEnabled: true},
+		},
+		"AdoptedBySpecKey": {
+			// The CustomProperty is named author, but manages the property
+			// plan. The property author must not be adopted.
+			props: map[string]clients.CustomProperty{
+				"author": {Key: "author", Description: "Post author", IsEnabled: true},
+				"plan":   {Key: "plan", Description: "Billing plan", IsEnabled: true},
+			},
+			cr:   customProperty("plan", stringPtr("Billing plan"), ""),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			obs:  custompropertyv1beta1.CustomPropertyObservation{Key: "plan", Description: "Billing plan", IsEnabled: true},
+		},
+		"KeyChanged": {
+			props: map[string]clients.CustomProperty{
+				"plan": {Key: "plan", IsEnabled: true},
+			},
+			cr:   customProperty("writer", nil, "plan"),
+			want: managed.ExternalObservation{ResourceExists: false},
+		},
+		"KeyChangedWhileDeleting": {
+			props: map[string]clients.CustomProperty{
+				"plan": {Key: "plan", IsEnabled: true},
+			},
+			cr:   deleting,
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			obs:  custompropertyv1beta1.CustomPropertyObservation{Key: "plan", IsEnabled: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, &fake.Plausible{CustomProperties: tc.props})
+
+			got, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.obs, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("Observe(...): atProvider -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		props       map[string]clients.CustomProperty
+		cr          *custompropertyv1beta1.CustomProperty
+		wantProps   []string
+		wantDeleted []string
+	}{
+		"Created": {
+			cr:        customProperty("author", stringPtr("Post author"), ""),
+			wantProps: []string{"author"},
+		},
+		"KeyChanged": {
+			props: map[string]clients.CustomProperty{
+				"writer": {Key: "writer", IsEnabled: true},
+			},
+			cr:          customProperty("author", stringPtr("Post author"), "writer"),
+			wantProps:   []string{"author"},
+			wantDeleted: []string{"writer"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fake.Plausible{CustomProperties: tc.props}
+			e := newExternal(t, f)
+
+			if _, err := e.Create(context.Background(), tc.cr); err != nil {
+				t.Fatalf("Create(...): unexpected error: %v", err)
+			}
+			if got := f.CustomProperties["author"].Description; got != "Post author" {
+				t.Errorf("Create(...): description = %q, want %q", got, "Post author")
+			}
+			if en := meta.GetExternalName(tc.cr); en != "author" {
+				t.Errorf("Create(...): external name = %q, want %q", en, "author")
+			}
+			if diff := cmp.Diff(tc.wantProps, slices.Sorted(maps.Keys(f.CustomProperties))); diff != "" {
+				t.Errorf("Create(...): properties -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, f.Deleted); diff != "" {
+				t.Errorf("Create(...): deleted -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	f := &fake.Plausible{CustomProperties: map[string]clients.CustomProperty{
+		"author": {Key: "author", Description: "old", IsEnabled: true},
+	}}
+	e := newExternal(t, f)
+	cr := customProperty("author", stringPtr("new"), "author")
+
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	if got := f.CustomProperties["author"].Description; got != "new" {
+		t.Errorf("Update(...): description = %q, want %q", got, "new")
+	}
+	if len(f.Deleted) != 0 {
+		t.Errorf("Update(...): deleted %q, want nothing deleted", f.Deleted)
+	}
+}
+
+// TestChangeKey changes the key of a CustomProperty and checks that it
+// converges, although the managed reconciler keeps no external name set by
+// Update.
+func TestChangeKey(t *testing.T) {
+	f := &fake.Plausible{}
+	e := newExternal(t, f)
+	cr := customProperty("writer", nil, "")
+	ctx := context.Background()
+
+	if err := fake.Reconcile(ctx, e, cr); err != nil {
+		t.Fatalf("Reconcile(...): unexpected error: %v", err)
+	}
+	cr.Spec.ForProvider.Key = "author"
+	for range 2 {
+		if err := fake.Reconcile(ctx, e, cr); err != nil {
+			t.Fatalf("Reconcile(...): unexpected error: %v", err)
+		}
+	}
+
+	if en := meta.GetExternalName(cr); en != "author" {
+		t.Errorf("external name = %q, want %q", en, "author")
+	}
+	if diff := cmp.Diff([]string{"author"}, slices.Sorted(maps.Keys(f.CustomProperties))); diff != "" {
+		t.Errorf("properties -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"writer"}, f.Deleted); diff != "" {
+		t.Errorf("deleted -want, +got:\n%s", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		props map[string]clients.CustomProperty
+	}{
+		"Successful": {
+			props: map[string]clients.CustomProperty{"author": {Key: "author"}},
+		},
+		"AlreadyDeleted": {
+			props: map[string]clients.CustomProperty{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fake.Plausible{CustomProperties: tc.props}
+			e := newExternal(t, f)
+
+			if _, err := e.Delete(context.Background(), customProperty("author", nil, "author")); err != nil {
+				t.Errorf("Delete(...): unexpected error: %v", err)
+			}
+			if _, ok := f.CustomProperties["author"]; ok {
+				t.Errorf("Delete(...): expected custom property to be removed")
+			}
+		})
+	}
+}
+
+// Helper function to create string pointers
+func stringPtr(s string) *string {
+	return &s
+}
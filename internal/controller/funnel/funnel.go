@@ -0,0 +1,296 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funnel
+
+import (
+	"context"
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	funnelv1beta1 "github.com/rossigee/provider-plausible/apis/funnel/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/tracing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotFunnel = "managed resource is not a Funnel custom resource"
+
+	errNoSiteDomain = "no site domain specified"
+	errNoGoalID     = "step %d of the funnel has no goal ID"
+	errGetFunnel    = "failed to get funnel"
+	errCreateFunnel = "failed to create funnel"
+	errUpdateFunnel = "failed to update funnel"
+	errDeleteFunnel = "failed to delete funnel"
+)
+
+// Setup adds a controller that reconciles Funnel managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(funnelv1beta1.FunnelGroupKind.String())
+	recorder := clients.NewEventRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(funnelv1beta1.FunnelGroupVersionKind),
+		managed.WithExternalConnector(&connector{
+			kube:         mgr.GetClient(),
+			usage:        clients.NewProviderConfigUsageTracker(mgr.GetClient()),
+			newServiceFn: clients.NewClient,
+			recorder:     recorder,
+		}),
+		// The external name is the Plausible funnel ID, so it must not default
+		// to the name of the Kubernetes object.
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithReferenceResolver(clients.NewSiteDomainReferenceResolver(mgr.GetClient(), forgetReferences, unselectSite)))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&funnelv1beta1.Funnel{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// forgetReferences clears the site domain and goal IDs of a Funnel that
+// references or selects its Site and references its Goals, so that they are
+// resolved again and the Funnel follows a renamed Site or a replaced Goal.
+func forgetReferences(mg resource.Managed) {
+	cr, ok := mg.(*funnelv1beta1.Funnel)
+	if !ok {
+		return
+	}
+	if cr.Spec.ForProvider.SiteDomainRef != nil || cr.Spec.ForProvider.SiteDomainSelector != nil {
+		cr.Spec.ForProvider.SiteDomain = nil
+	}
+	for i := range cr.Spec.ForProvider.Steps {
+		if cr.Spec.ForProvider.Steps[i].GoalIDRef != nil {
+			cr.Spec.ForProvider.Steps[i].GoalID = nil
+		}
+	}
+}
+
+// unselectSite clears the Site reference a siteDomainSelector resolved to, so
+// that the Funnel keeps only the selector and is validated as it was applied.
+func unselectSite(mg resource.Managed) {
+	if cr, ok := mg.(*funnelv1beta1.Funnel); ok && cr.Spec.ForProvider.SiteDomainSelector != nil {
+		cr.Spec.ForProvider.SiteDomainRef = nil
+	}
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(config clients.Config) *clients.Client
+	recorder     event.Recorder
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*funnelv1beta1.Funnel)
+	if !ok {
+		return nil, errors.New(errNotFunnel)
+	}
+
+	cfg, err := clients.GetConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := c.newServiceFn(*cfg)
+
+	return clients.WithEvents(clients.WithErrorConditions(&external{service: svc, kube: c.kube}), c.recorder), nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	service *clients.Client
+	kube    client.Client
+}
+
+// getSiteDomain returns the domain of the site the funnel belongs to. Any
+// siteDomainRef or siteDomainSelector has already been resolved into
+// siteDomain by the time the managed reconciler calls the external client.
+func getSiteDomain(cr *funnelv1beta1.Funnel) (string, error) {
+	if cr.Spec.ForProvider.SiteDomain == nil || *cr.Spec.ForProvider.SiteDomain == "" {
+		return "", errors.New(errNoSiteDomain)
+	}
+	return *cr.Spec.ForProvider.SiteDomain, nil
+}
+
+// funnelRequest returns the request that creates or updates the funnel
+// described by cr.
+func funnelRequest(cr *funnelv1beta1.Funnel) (clients.FunnelRequest, error) {
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return clients.FunnelRequest{}, err
+	}
+
+	req := clients.FunnelRequest{SiteDomain: siteDomain, Name: cr.Spec.ForProvider.Name}
+	for i, s := range cr.Spec.ForProvider.Steps {
+		if s.GoalID == nil || *s.GoalID == "" {
+			return clients.FunnelRequest{}, errors.Errorf(errNoGoalID, i+1)
+		}
+		req.Steps = append(req.Steps, clients.FunnelStep{GoalID: *s.GoalID})
+	}
+	return req, nil
+}
+
+func observation(f *clients.Funnel) funnelv1beta1.FunnelObservation {
+	o := funnelv1beta1.FunnelObservation{ID: f.ID, Name: f.Name}
+	for _, s := range f.Steps {
+		o.GoalIDs = append(o.GoalIDs, s.GoalID)
+	}
+	return o
+}
+
+// isUpToDate returns true if the funnel has the name and steps of req.
+func isUpToDate(req clients.FunnelRequest, f *clients.Funnel) bool {
+	return req.Name == f.Name && slices.Equal(req.Steps, f.Steps)
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*funnelv1beta1.Funnel)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotFunnel)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "funnel.observe", "Funnel", cr.GetName(), "observe")
+	defer span.End()
+
+	req, err := funnelRequest(cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// Adopt an existing funnel of the same name until the ID is known.
+	var funnel *clients.Funnel
+	id := meta.GetExternalName(cr)
+	if id != "" {
+		funnel, err = c.service.GetFunnel(ctx, req.SiteDomain, id)
+	} else {
+		funnel, err = c.service.GetFunnelByName(ctx, req.SiteDomain, req.Name)
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetFunnel)
+	}
+	if funnel == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider = observation(funnel)
+	cr.SetConditions(xpv1.Available())
+
+	adopted := id != funnel.ID
+	if adopted {
+		meta.SetExternalName(cr, funnel.ID)
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        isUpToDate(req, funnel),
+		ResourceLateInitialized: adopted,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*funnelv1beta1.Funnel)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotFunnel)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "funnel.create", "Funnel", cr.GetName(), "create")
+	defer span.End()
+
+	cr.SetConditions(xpv1.Creating())
+
+	req, err := funnelRequest(cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	funnel, err := c.service.CreateFunnel(ctx, req)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFunnel)
+	}
+
+	meta.SetExternalName(cr, funnel.ID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*funnelv1beta1.Funnel)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotFunnel)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "funnel.update", "Funnel", cr.GetName(), "update")
+	defer span.End()
+
+	req, err := funnelRequest(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if _, err := c.service.UpdateFunnel(ctx, meta.GetExternalName(cr), req); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFunnel)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*funnelv1beta1.Funnel)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotFunnel)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "funnel.delete", "Funnel", cr.GetName(), "delete")
+	defer span.End()
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	err = c.service.DeleteFunnel(ctx, siteDomain, meta.GetExternalName(cr))
+	if err != nil && !clients.IsNotFound(err) {
+		return managed.ExternalDelete{}, errors.Wrap(err, errDeleteFunnel)
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	// Nothing to disconnect for Plausible API client
+	return nil
+}
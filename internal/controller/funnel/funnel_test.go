@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funnel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	funnelv1beta1 "github.com/rossigee/provider-plausible/apis/funnel/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+)
+
+func funnel(externalName string, goalIDs ...string) *funnelv1beta1.Funnel {
+	cr := &funnelv1beta1.Funnel{
+		Spec: funnelv1beta1.FunnelSpec{
+			ForProvider: funnelv1beta1.FunnelParameters{
+				SiteDomain: ptr("example.com"),
+				Name:       "Checkout",
+			},
+		},
+	}
+	if externalName != "" {
+		cr.SetAnnotations(map[string]string{meta.AnnotationKeyExternalName: externalName})
+	}
+	for _, id := range goalIDs {
+		cr.Spec.ForProvider.Steps = append(cr.Spec.ForProvider.Steps, funnelv1beta1.FunnelStep{GoalID: ptr(id)})
+	}
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	existing := clients.Funnel{
+		ID:    "10",
+		Name:  "Checkout",
+		Steps: []clients.FunnelStep{{GoalID: "1"}, {GoalID: "2"}},
+	}
+
+	cases := map[string]struct {
+		cr               *funnelv1beta1.Funnel
+		want             managed.ExternalObservation
+		wantExternalName string
+	}{
+		"UpToDate": {
+			cr:               funnel("10", "1", "2"),
+			want:             managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			wantExternalName: "10",
+		},
+		"StepsChanged": {
+			cr:               funnel("10", "2", "1"),
+			want:             managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			wantExternalName: "10",
+		},
+		"AdoptedByName": {
+			cr:               funnel("", "1", "2"),
+			want:             managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			wantExternalName: "10",
+		},
+		"Deleted": {
+			cr:               funnel("11", "1", "2"),
+			want:             managed.ExternalObservation{ResourceExists: false},
+			wantExternalName: "11",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(clients.ListFunnelsResponse{Funnels: []clients.Funnel{existing}})
+			}))
+			defer srv.Close()
+
+			e := &external{service: clients.NewClient(clients.Config{BaseURL: srv.URL, APIKey: "test-key"})}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if en := meta.GetExternalName(tc.cr); en != tc.wantExternalName {
+				t.Errorf("Observe(...): external name %q, want %q", en, tc.wantExternalName)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var got clients.FunnelRequest
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(clients.Funnel{ID: "10"})
+	}))
+	defer srv.Close()
+
+	e := &external{service: clients.NewClient(clients.Config{BaseURL: srv.URL, APIKey: "test-key"})}
+	if _, err := e.Update(context.Background(), funnel("10", "2", "1")); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+
+	if path != "PUT /api/v1/sites/funnels/10" {
+		t.Errorf("Update(...): sent %q, want %q", path, "PUT /api/v1/sites/funnels/10")
+	}
+	want := clients.FunnelRequest{
+		SiteDomain: "example.com",
+		Name:       "Checkout",
+		Steps:      []clients.FunnelStep{{GoalID: "2"}, {GoalID: "1"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Update(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestFunnelRequestUnresolvedStep(t *testing.T) {
+	cr := funnel("10", "1")
+	cr.Spec.ForProvider.Steps = append(cr.Spec.ForProvider.Steps, funnelv1beta1.FunnelStep{
+		GoalIDRef: &xpv1.Reference{Name: "purchase"},
+	})
+
+	if _, err := funnelRequest(cr); err == nil || err.Error() != "step 2 of the funnel has no goal ID" {
+		t.Errorf("funnelRequest(...): got error %v, want unresolved step 2", err)
+	}
+}
+
+func TestForgetReferences(t *testing.T) {
+	cr := funnel("10", "1", "2")
+	cr.Spec.ForProvider.SiteDomainRef = &xpv1.Reference{Name: "site"}
+	cr.Spec.ForProvider.Steps[1].GoalIDRef = &xpv1.Reference{Name: "purchase"}
+
+	forgetReferences(cr)
+
+	if cr.Spec.ForProvider.SiteDomain != nil {
+		t.Error("forgetReferences(...): site domain with a reference was kept")
+	}
+	if cr.Spec.ForProvider.Steps[0].GoalID == nil {
+		t.Error("forgetReferences(...): goal ID without a reference was cleared")
+	}
+	if cr.Spec.ForProvider.Steps[1].GoalID != nil {
+		t.Error("forgetReferences(...): goal ID with a reference was kept")
+	}
+}
+
+func TestUnselectSite(t *testing.T) {
+	cr := funnel("10", "1")
+	cr.Spec.ForProvider.SiteDomainSelector = &xpv1.Selector{MatchLabels: map[string]string{"site": "main"}}
+
+	forgetReferences(cr)
+	if cr.Spec.ForProvider.SiteDomain != nil {
+		t.Error("forgetReferences(...): site domain with a selector was kept")
+	}
+
+	cr.Spec.ForProvider.SiteDomain = ptr("example.com")
+	cr.Spec.ForProvider.SiteDomainRef = &xpv1.Reference{Name: "site"}
+	unselectSite(cr)
+	if cr.Spec.ForProvider.SiteDomainRef != nil {
+		t.Error("unselectSite(...): selected site reference was kept")
+	}
+	if cr.Spec.ForProvider.SiteDomain == nil {
+		t.Error("unselectSite(...): selected site domain was cleared")
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
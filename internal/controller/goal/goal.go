@@ -18,8 +18,11 @@ package goal
 
 import (
 	"context"
+	"maps"
+	"slices"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -27,10 +30,8 @@ import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/pkg/errors"
 	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
-	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
 	"github.com/rossigee/provider-plausible/internal/clients"
 	"github.com/rossigee/provider-plausible/internal/tracing"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -41,13 +42,30 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errGetCreds     = "cannot get credentials"
 
-	errNewClient = "cannot create new Service"
-	errGetSite   = "cannot get referenced Site"
+	errNewClient    = "cannot create new Service"
+	errNoSiteDomain = "no site domain specified"
+
+	errNoEventName          = "event name is required for event goals"
+	errNoPagePath           = "page path is required for page goals"
+	errCurrencyPage         = "currency is only supported for event goals"
+	errScrollThresholdEvent = "scroll threshold is only supported for page goals"
+	errReplaceGoal          = "failed to replace goal"
+	errDeleteReplaced       = "failed to delete replaced goal"
+	errReplaceNever         = "goal differs from its spec, but its replacePolicy is Never"
+	errReplaceNotManaged    = "goal differs from its spec, but its managementPolicies do not allow replacing it"
+)
+
+// Reasons for the Drifted condition of a goal that differs from its spec but
+// must not be replaced.
+const (
+	reasonReplacePolicyNever xpv1.ConditionReason = "ReplacePolicyNever"
+	reasonReplaceNotManaged  xpv1.ConditionReason = "ReplaceNotManaged"
 )
 
 // Setup adds a controller that reconciles Goal managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(goalv1beta1.GoalGroupKind.String())
+	recorder := clients.NewEventRecorder(mgr.GetEventRecorderFor(name))
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(goalv1beta1.GoalGroupVersionKind),
@@ -55,11 +73,12 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			kube:         mgr.GetClient(),
 			usage:        clients.NewProviderConfigUsageTracker(mgr.GetClient()),
 			newServiceFn: clients.NewClient,
+			recorder:     recorder,
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(nil),
-		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())))
+		managed.WithRecorder(recorder),
+		managed.WithReferenceResolver(clients.NewSiteDomainReferenceResolver(mgr.GetClient(), forgetSiteDomain, unselectSite)))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -69,12 +88,29 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// forgetSiteDomain clears the site domain of a Goal that references or selects its Site,
+// so that it is resolved again and follows the Site if it is renamed.
+func forgetSiteDomain(mg resource.Managed) {
+	if cr, ok := mg.(*goalv1beta1.Goal); ok && (cr.Spec.ForProvider.SiteDomainRef != nil || cr.Spec.ForProvider.SiteDomainSelector != nil) {
+		cr.Spec.ForProvider.SiteDomain = nil
+	}
+}
+
+// unselectSite clears the Site reference a siteDomainSelector resolved to, so
+// that the Goal keeps only the selector and is validated as it was applied.
+func unselectSite(mg resource.Managed) {
+	if cr, ok := mg.(*goalv1beta1.Goal); ok && cr.Spec.ForProvider.SiteDomainSelector != nil {
+		cr.Spec.ForProvider.SiteDomainRef = nil
+	}
+}
+
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
 	newServiceFn func(config clients.Config) *clients.Client
+	recorder     event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -95,7 +131,7 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 
 	svc := c.newServiceFn(*cfg)
 
-	return &external{service: svc, kube: c.kube}, nil
+	return clients.WithEvents(clients.WithErrorConditions(&external{service: svc, kube: c.kube}), c.recorder), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -105,30 +141,14 @@ type external struct {
 	kube    client.Client
 }
 
-func (c *external) getSiteDomain(ctx context.Context, cr *goalv1beta1.Goal) (string, error) {
-	// If direct domain is specified, use it
-	if cr.Spec.ForProvider.SiteDomain != nil && *cr.Spec.ForProvider.SiteDomain != "" {
-		return *cr.Spec.ForProvider.SiteDomain, nil
+// getSiteDomain returns the domain of the site the goal belongs to. Any
+// siteDomainRef or siteDomainSelector has already been resolved into
+// siteDomain by the time the managed reconciler calls the external client.
+func getSiteDomain(cr *goalv1beta1.Goal) (string, error) {
+	if cr.Spec.ForProvider.SiteDomain == nil || *cr.Spec.ForProvider.SiteDomain == "" {
+		return "", errors.New(errNoSiteDomain)
 	}
-
-	// If reference is specified, resolve it
-	if cr.Spec.ForProvider.SiteDomainRef != nil {
-		site := &sitev1beta1.Site{}
-		nn := types.NamespacedName{
-			Name: cr.Spec.ForProvider.SiteDomainRef.Name,
-		}
-		if err := c.kube.Get(ctx, nn, site); err != nil {
-			return "", errors.Wrap(err, errGetSite)
-		}
-		return site.Spec.ForProvider.Domain, nil
-	}
-
-	// If selector is specified, we don't support it in this simple implementation
-	if cr.Spec.ForProvider.SiteDomainSelector != nil {
-		return "", errors.New("site domain selector is not yet implemented")
-	}
-
-	return "", errors.New("no site domain specified")
+	return *cr.Spec.ForProvider.SiteDomain, nil
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -139,55 +159,60 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	ctx, span := tracing.StartSpanWithAttrs(ctx, "goal.observe", "Goal", cr.GetName(), "observe")
 	defer span.End()
 
-	siteDomain, err := c.getSiteDomain(ctx, cr)
+	siteDomain, err := getSiteDomain(cr)
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
 
 	// If we have an external name (goal ID), try to get it
 	if meta.GetExternalName(cr) != "" {
-		goal, err := c.service.GetGoal(siteDomain, meta.GetExternalName(cr))
+		goal, err := c.service.GetGoal(ctx, siteDomain, meta.GetExternalName(cr))
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, "failed to get goal")
 		}
 
 		if goal == nil {
+			cr.Status.AtProvider = goalv1beta1.GoalObservation{}
 			return managed.ExternalObservation{
 				ResourceExists: false,
 			}, nil
 		}
 
-		cr.Status.AtProvider = goalv1beta1.GoalObservation{
-			ID:        goal.ID,
-			GoalType:  goal.GoalType,
-			EventName: goal.EventName,
-			PagePath:  goal.PagePath,
-		}
+		cr.Status.AtProvider = observation(goal)
 
 		cr.SetConditions(xpv1.Available())
 
+		upToDate := goalMatches(cr.Spec.ForProvider, goal)
+		if !upToDate && replaceable(cr) {
+			// Report the goal as missing so that Create replaces it. Unlike
+			// Update, Create can change the external name.
+			return managed.ExternalObservation{
+				ResourceExists: false,
+			}, nil
+		}
+		if managesLifecycle(cr) {
+			clients.SetDrifted(cr, !upToDate, reasonReplacePolicyNever, errReplaceNever)
+		} else {
+			clients.SetDrifted(cr, !upToDate, reasonReplaceNotManaged, errReplaceNotManaged)
+		}
+
 		return managed.ExternalObservation{
 			ResourceExists:   true,
-			ResourceUpToDate: true, // Goals cannot be updated
+			ResourceUpToDate: true,
 		}, nil
 	}
 
 	// If no external name, try to find by matching goal properties
-	goals, err := c.service.ListGoals(siteDomain)
+	goals, err := c.service.ListGoals(ctx, siteDomain)
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, "failed to list goals")
 	}
 
 	for _, goal := range goals {
-		if c.goalMatches(cr, &goal) {
+		if goalMatches(cr.Spec.ForProvider, &goal) {
 			meta.SetExternalName(cr, goal.ID)
 
-			cr.Status.AtProvider = goalv1beta1.GoalObservation{
-				ID:        goal.ID,
-				GoalType:  goal.GoalType,
-				EventName: goal.EventName,
-				PagePath:  goal.PagePath,
-			}
+			cr.Status.AtProvider = observation(&goal)
 
 			cr.SetConditions(xpv1.Available())
 
@@ -198,24 +223,114 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		}
 	}
 
+	cr.Status.AtProvider = goalv1beta1.GoalObservation{}
 	return managed.ExternalObservation{
 		ResourceExists: false,
 	}, nil
 }
 
-func (c *external) goalMatches(cr *goalv1beta1.Goal, goal *clients.Goal) bool {
-	if cr.Spec.ForProvider.GoalType != goal.GoalType {
+// replaceable returns true if the goal of cr may be replaced when it no
+// longer matches the spec.
+func replaceable(cr *goalv1beta1.Goal) bool {
+	return cr.GetDeletionTimestamp() == nil && cr.Spec.ForProvider.ReplacePolicy != goalv1beta1.ReplacePolicyNever && managesLifecycle(cr)
+}
+
+// managesLifecycle returns true if the management policies of cr allow the
+// provider to create and delete goals, which replacing a goal does. No
+// management policies means the default, which allows all actions.
+func managesLifecycle(cr *goalv1beta1.Goal) bool {
+	p := cr.GetManagementPolicies()
+	return len(p) == 0 || slices.Contains(p, xpv1.ManagementActionAll) ||
+		(slices.Contains(p, xpv1.ManagementActionCreate) && slices.Contains(p, xpv1.ManagementActionDelete))
+}
+
+func observation(goal *clients.Goal) goalv1beta1.GoalObservation {
+	return goalv1beta1.GoalObservation{
+		ID:              goal.ID,
+		GoalType:        goal.GoalType,
+		EventName:       goal.EventName,
+		PagePath:        goal.PagePath,
+		DisplayName:     goal.DisplayName,
+		Currency:        goal.Currency,
+		ScrollThreshold: goal.ScrollThreshold,
+		CustomProps:     goal.CustomProps,
+	}
+}
+
+// goalMatches returns true if goal is the goal described by p. Goals cannot
+// be changed in Plausible, so a goal that does not match has to be replaced.
+func goalMatches(p goalv1beta1.GoalParameters, goal *clients.Goal) bool {
+	if p.GoalType != goal.GoalType {
 		return false
 	}
 
-	switch cr.Spec.ForProvider.GoalType {
+	switch p.GoalType {
 	case "event":
-		return cr.Spec.ForProvider.EventName != nil && *cr.Spec.ForProvider.EventName == goal.EventName
+		if p.EventName == nil || *p.EventName != goal.EventName {
+			return false
+		}
+		if value(p.Currency) != goal.Currency {
+			return false
+		}
 	case "page":
-		return cr.Spec.ForProvider.PagePath != nil && *cr.Spec.ForProvider.PagePath == goal.PagePath
+		if p.PagePath == nil || *p.PagePath != goal.PagePath {
+			return false
+		}
+		// Plausible reports page goals without a threshold as -1.
+		if value(p.ScrollThreshold) != max(goal.ScrollThreshold, 0) {
+			return false
+		}
+	default:
+		return false
 	}
 
-	return false
+	// Plausible names goals after their event or page unless told otherwise.
+	if p.DisplayName != nil && *p.DisplayName != goal.DisplayName {
+		return false
+	}
+
+	return maps.Equal(p.CustomProps, goal.CustomProps)
+}
+
+// value returns the value v points to, or the zero value if v is nil.
+func value[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
+
+// createRequest returns the request that creates the goal described by p.
+func createRequest(p goalv1beta1.GoalParameters) (clients.CreateGoalRequest, error) {
+	req := clients.CreateGoalRequest{
+		GoalType:    p.GoalType,
+		DisplayName: value(p.DisplayName),
+		CustomProps: p.CustomProps,
+	}
+
+	switch p.GoalType {
+	case "event":
+		if p.EventName == nil {
+			return req, errors.New(errNoEventName)
+		}
+		if p.ScrollThreshold != nil {
+			return req, errors.New(errScrollThresholdEvent)
+		}
+		req.EventName = *p.EventName
+		req.Currency = value(p.Currency)
+	case "page":
+		if p.PagePath == nil {
+			return req, errors.New(errNoPagePath)
+		}
+		if p.Currency != nil {
+			return req, errors.New(errCurrencyPage)
+		}
+		req.PagePath = *p.PagePath
+		req.ScrollThreshold = value(p.ScrollThreshold)
+	}
+
+	return req, nil
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -228,35 +343,47 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	cr.SetConditions(xpv1.Creating())
 
-	siteDomain, err := c.getSiteDomain(ctx, cr)
+	siteDomain, err := getSiteDomain(cr)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
 
-	req := clients.CreateGoalRequest{
-		GoalType: cr.Spec.ForProvider.GoalType,
+	req, err := createRequest(cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, err
 	}
 
-	switch cr.Spec.ForProvider.GoalType {
-	case "event":
-		if cr.Spec.ForProvider.EventName == nil {
-			return managed.ExternalCreation{}, errors.New("event name is required for event goals")
-		}
-		req.EventName = *cr.Spec.ForProvider.EventName
-	case "page":
-		if cr.Spec.ForProvider.PagePath == nil {
-			return managed.ExternalCreation{}, errors.New("page path is required for page goals")
-		}
-		req.PagePath = *cr.Spec.ForProvider.PagePath
-	}
+	// Observe reports a goal that no longer matches the spec as missing, so
+	// it is replaced here.
+	replaced := cr.Status.AtProvider.ID
 
-	goal, err := c.service.CreateGoal(siteDomain, req)
+	goal, err := c.service.CreateGoal(ctx, siteDomain, req)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to create goal")
 	}
 
+	// Plausible returns the existing goal rather than creating a second one
+	// for the same event or page. The old goal has to go first in that case.
+	if replaced != "" && goal.ID == replaced {
+		if err := c.service.DeleteGoal(ctx, replaced); err != nil && !clients.IsNotFound(err) {
+			return managed.ExternalCreation{}, errors.Wrap(err, errReplaceGoal)
+		}
+		replaced = ""
+
+		goal, err = c.service.CreateGoal(ctx, siteDomain, req)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errReplaceGoal)
+		}
+	}
+
 	meta.SetExternalName(cr, goal.ID)
 
+	if replaced != "" {
+		if err := c.service.DeleteGoal(ctx, replaced); err != nil && !clients.IsNotFound(err) {
+			return managed.ExternalCreation{}, errors.Wrap(err, errDeleteReplaced)
+		}
+	}
+
 	return managed.ExternalCreation{}, nil
 }
 
@@ -266,12 +393,12 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotGoal)
 	}
 
-	ctx, span := tracing.StartSpanWithAttrs(ctx, "goal.update", "Goal", cr.GetName(), "update")
+	_, span := tracing.StartSpanWithAttrs(ctx, "goal.update", "Goal", cr.GetName(), "update")
 	defer span.End()
 
-	// Goals cannot be updated, they are immutable
-	_ = cr
-	_ = ctx
+	// Goals cannot be changed in Plausible. A goal that differs from its spec
+	// is either replaced by Create or, if its replacePolicy is Never, left as
+	// it is and reported by the Drifted condition.
 
 	return managed.ExternalUpdate{}, nil
 }
@@ -282,10 +409,10 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotGoal)
 	}
 
-	_, span := tracing.StartSpanWithAttrs(ctx, "goal.delete", "Goal", cr.GetName(), "delete")
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "goal.delete", "Goal", cr.GetName(), "delete")
 	defer span.End()
 
-	err := c.service.DeleteGoal(meta.GetExternalName(cr))
+	err := c.service.DeleteGoal(ctx, meta.GetExternalName(cr))
 	if err != nil && !clients.IsNotFound(err) {
 		return managed.ExternalDelete{}, errors.Wrap(err, "failed to delete goal")
 	}
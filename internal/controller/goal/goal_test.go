@@ -18,18 +18,22 @@ package goal
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
-	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
-	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
 	"github.com/rossigee/provider-plausible/internal/clients"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -48,54 +52,13 @@ type testExternal struct {
 	kube    client.Client
 }
 
-func (c *testExternal) getSiteDomain(ctx context.Context, cr *goalv1beta1.Goal) (string, error) {
-	// If direct domain is specified, use it
-	if cr.Spec.ForProvider.SiteDomain != nil && *cr.Spec.ForProvider.SiteDomain != "" {
-		return *cr.Spec.ForProvider.SiteDomain, nil
-	}
-
-	// If reference is specified, resolve it
-	if cr.Spec.ForProvider.SiteDomainRef != nil {
-		site := &sitev1beta1.Site{}
-		nn := client.ObjectKey{
-			Name: cr.Spec.ForProvider.SiteDomainRef.Name,
-		}
-		if err := c.kube.Get(ctx, nn, site); err != nil {
-			return "", errors.Wrap(err, errGetSite)
-		}
-		return site.Spec.ForProvider.Domain, nil
-	}
-
-	// If selector is specified, we don't support it in this simple implementation
-	if cr.Spec.ForProvider.SiteDomainSelector != nil {
-		return "", errors.New("site domain selector is not yet implemented")
-	}
-
-	return "", errors.New("no site domain specified")
-}
-
-func (c *testExternal) goalMatches(cr *goalv1beta1.Goal, goal *clients.Goal) bool {
-	if cr.Spec.ForProvider.GoalType != goal.GoalType {
-		return false
-	}
-
-	switch cr.Spec.ForProvider.GoalType {
-	case "event":
-		return cr.Spec.ForProvider.EventName != nil && *cr.Spec.ForProvider.EventName == goal.EventName
-	case "page":
-		return cr.Spec.ForProvider.PagePath != nil && *cr.Spec.ForProvider.PagePath == goal.PagePath
-	}
-
-	return false
-}
-
 func (c *testExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	cr, ok := mg.(*goalv1beta1.Goal)
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotGoal)
 	}
 
-	siteDomain, err := c.getSiteDomain(ctx, cr)
+	siteDomain, err := getSiteDomain(cr)
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
@@ -124,7 +87,7 @@ func (c *testExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 
 		return managed.ExternalObservation{
 			ResourceExists:   true,
-			ResourceUpToDate: true, // Goals cannot be updated
+			ResourceUpToDate: goalMatches(cr.Spec.ForProvider, goal),
 		}, nil
 	}
 
@@ -135,7 +98,7 @@ func (c *testExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 	}
 
 	for _, goal := range goals {
-		if c.goalMatches(cr, &goal) {
+		if goalMatches(cr.Spec.ForProvider, &goal) {
 			meta.SetExternalName(cr, goal.ID)
 
 			cr.Status.AtProvider = goalv1beta1.GoalObservation{
@@ -167,7 +130,7 @@ func (c *testExternal) Create(ctx context.Context, mg resource.Managed) (managed
 
 	cr.SetConditions(xpv1.Creating())
 
-	siteDomain, err := c.getSiteDomain(ctx, cr)
+	siteDomain, err := getSiteDomain(cr)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
@@ -698,7 +661,7 @@ func TestDelete(t *testing.T) {
 			e: testExternal{
 				service: &mockPlausibleService{
 					deleteGoalFn: func(goalID string) error {
-						return errors.New("API request failed: status 404")
+						return &clients.APIError{StatusCode: 404, Message: "Not Found"}
 					},
 				},
 			},
@@ -743,10 +706,9 @@ func TestDelete(t *testing.T) {
 
 func TestGetSiteDomain(t *testing.T) {
 	cases := map[string]struct {
-		goal     *goalv1beta1.Goal
-		mockSite *sitev1beta1.Site
-		want     string
-		wantErr  bool
+		goal    *goalv1beta1.Goal
+		want    string
+		wantErr bool
 	}{
 		"DirectDomain": {
 			goal: &goalv1beta1.Goal{
@@ -758,33 +720,26 @@ func TestGetSiteDomain(t *testing.T) {
 			},
 			want: "example.com",
 		},
-		"DomainReference": {
+		"ResolvedReference": {
 			goal: &goalv1beta1.Goal{
 				Spec: goalv1beta1.GoalSpec{
 					ForProvider: goalv1beta1.GoalParameters{
+						SiteDomain: stringPtr("example.com"),
 						SiteDomainRef: &xpv1.Reference{
 							Name: "test-site",
 						},
 					},
 				},
 			},
-			mockSite: &sitev1beta1.Site{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "test-site",
-				},
-				Spec: sitev1beta1.SiteSpec{
-					ForProvider: sitev1beta1.SiteParameters{
-						Domain: "example.com",
-					},
-				},
-			},
 			want: "example.com",
 		},
-		"SelectorNotSupported": {
+		"UnresolvedReference": {
 			goal: &goalv1beta1.Goal{
 				Spec: goalv1beta1.GoalSpec{
 					ForProvider: goalv1beta1.GoalParameters{
-						SiteDomainSelector: &xpv1.Selector{},
+						SiteDomainRef: &xpv1.Reference{
+							Name: "test-site",
+						},
 					},
 				},
 			},
@@ -802,22 +757,7 @@ func TestGetSiteDomain(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			kube := &test.MockClient{
-				MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
-					if tc.mockSite != nil {
-						site := obj.(*sitev1beta1.Site)
-						tc.mockSite.DeepCopyInto(site)
-						return nil
-					}
-					return errors.New("site not found")
-				},
-			}
-
-			e := testExternal{
-				kube: kube,
-			}
-
-			got, err := e.getSiteDomain(context.Background(), tc.goal)
+			got, err := getSiteDomain(tc.goal)
 
 			if tc.wantErr {
 				if err == nil {
@@ -904,6 +844,109 @@ func TestGoalMatches(t *testing.T) {
 			},
 			want: false,
 		},
+		"RevenueGoalMatches": {
+			cr: &goalv1beta1.Goal{
+				Spec: goalv1beta1.GoalSpec{
+					ForProvider: goalv1beta1.GoalParameters{
+						GoalType:    "event",
+						EventName:   stringPtr("Purchase"),
+						Currency:    stringPtr("EUR"),
+						CustomProps: map[string]string{"plan": "pro"},
+					},
+				},
+			},
+			goal: &clients.Goal{
+				GoalType:    "event",
+				EventName:   "Purchase",
+				DisplayName: "Purchase",
+				Currency:    "EUR",
+				CustomProps: map[string]string{"plan": "pro"},
+			},
+			want: true,
+		},
+		"CurrencyChanged": {
+			cr: &goalv1beta1.Goal{
+				Spec: goalv1beta1.GoalSpec{
+					ForProvider: goalv1beta1.GoalParameters{
+						GoalType:  "event",
+						EventName: stringPtr("Purchase"),
+						Currency:  stringPtr("USD"),
+					},
+				},
+			},
+			goal: &clients.Goal{
+				GoalType:  "event",
+				EventName: "Purchase",
+				Currency:  "EUR",
+			},
+			want: false,
+		},
+		"DisplayNameChanged": {
+			cr: &goalv1beta1.Goal{
+				Spec: goalv1beta1.GoalSpec{
+					ForProvider: goalv1beta1.GoalParameters{
+						GoalType:    "event",
+						EventName:   stringPtr("signup"),
+						DisplayName: stringPtr("Sign up"),
+					},
+				},
+			},
+			goal: &clients.Goal{
+				GoalType:    "event",
+				EventName:   "signup",
+				DisplayName: "signup",
+			},
+			want: false,
+		},
+		"CustomPropsChanged": {
+			cr: &goalv1beta1.Goal{
+				Spec: goalv1beta1.GoalSpec{
+					ForProvider: goalv1beta1.GoalParameters{
+						GoalType:  "event",
+						EventName: stringPtr("signup"),
+					},
+				},
+			},
+			goal: &clients.Goal{
+				GoalType:    "event",
+				EventName:   "signup",
+				CustomProps: map[string]string{"plan": "pro"},
+			},
+			want: false,
+		},
+		"PageGoalWithoutScrollThreshold": {
+			cr: &goalv1beta1.Goal{
+				Spec: goalv1beta1.GoalSpec{
+					ForProvider: goalv1beta1.GoalParameters{
+						GoalType: "page",
+						PagePath: stringPtr("/blog"),
+					},
+				},
+			},
+			goal: &clients.Goal{
+				GoalType:        "page",
+				PagePath:        "/blog",
+				ScrollThreshold: -1,
+			},
+			want: true,
+		},
+		"ScrollThresholdChanged": {
+			cr: &goalv1beta1.Goal{
+				Spec: goalv1beta1.GoalSpec{
+					ForProvider: goalv1beta1.GoalParameters{
+						GoalType:        "page",
+						PagePath:        stringPtr("/blog"),
+						ScrollThreshold: intPtr(50),
+					},
+				},
+			},
+			goal: &clients.Goal{
+				GoalType:        "page",
+				PagePath:        "/blog",
+				ScrollThreshold: 75,
+			},
+			want: false,
+		},
 		"TypeMismatch": {
 			cr: &goalv1beta1.Goal{
 				Spec: goalv1beta1.GoalSpec{
@@ -923,8 +966,7 @@ func TestGoalMatches(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := testExternal{}
-			got := e.goalMatches(tc.cr, tc.goal)
+			got := goalMatches(tc.cr.Spec.ForProvider, tc.goal)
 
 			if got != tc.want {
 				t.Errorf("goalMatches(...): got %v, want %v", got, tc.want)
@@ -933,7 +975,259 @@ func TestGoalMatches(t *testing.T) {
 	}
 }
 
+func TestCreateRequest(t *testing.T) {
+	cases := map[string]struct {
+		params  goalv1beta1.GoalParameters
+		want    clients.CreateGoalRequest
+		wantErr string
+	}{
+		"RevenueGoal": {
+			params: goalv1beta1.GoalParameters{
+				GoalType:    "event",
+				EventName:   stringPtr("Purchase"),
+				DisplayName: stringPtr("Purchases"),
+				Currency:    stringPtr("EUR"),
+			},
+			want: clients.CreateGoalRequest{
+				GoalType:    "event",
+				EventName:   "Purchase",
+				DisplayName: "Purchases",
+				Currency:    "EUR",
+			},
+		},
+		"ScrollDepthGoal": {
+			params: goalv1beta1.GoalParameters{
+				GoalType:        "page",
+				PagePath:        stringPtr("/blog/**"),
+				ScrollThreshold: intPtr(75),
+				CustomProps:     map[string]string{"author": "jane"},
+			},
+			want: clients.CreateGoalRequest{
+				GoalType:        "page",
+				PagePath:        "/blog/**",
+				ScrollThreshold: 75,
+				CustomProps:     map[string]string{"author": "jane"},
+			},
+		},
+		"CurrencyOnPageGoal": {
+			params: goalv1beta1.GoalParameters{
+				GoalType: "page",
+				PagePath: stringPtr("/checkout"),
+				Currency: stringPtr("USD"),
+			},
+			wantErr: errCurrencyPage,
+		},
+		"ScrollThresholdOnEventGoal": {
+			params: goalv1beta1.GoalParameters{
+				GoalType:        "event",
+				EventName:       stringPtr("signup"),
+				ScrollThreshold: intPtr(50),
+			},
+			wantErr: errScrollThresholdEvent,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := createRequest(tc.params)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("createRequest(...): got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("createRequest(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("createRequest(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// goalServer fakes the Plausible goals API. Like Plausible, it returns the
+// existing goal when asked to create a goal for the same event or page.
+func goalServer(t *testing.T, goals map[string]clients.Goal) *httptest.Server {
+	t.Helper()
+	next := len(goals) + 1
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			resp := clients.ListGoalsResponse{}
+			for _, g := range goals {
+				resp.Goals = append(resp.Goals, g)
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case http.MethodPut:
+			var req clients.CreateGoalRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			for _, g := range goals {
+				if g.EventName == req.EventName && g.PagePath == req.PagePath {
+					_ = json.NewEncoder(w).Encode(g)
+					return
+				}
+			}
+			g := clients.Goal{
+				ID:          fmt.Sprintf("goal-%d", next),
+				GoalType:    req.GoalType,
+				EventName:   req.EventName,
+				PagePath:    req.PagePath,
+				DisplayName: req.DisplayName,
+				Currency:    req.Currency,
+			}
+			next++
+			goals[g.ID] = g
+			_ = json.NewEncoder(w).Encode(g)
+		case http.MethodDelete:
+			id := strings.TrimPrefix(r.URL.Path, "/api/v1/sites/goals/")
+			if _, ok := goals[id]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(goals, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestReplace(t *testing.T) {
+	cases := map[string]struct {
+		existing    clients.Goal
+		params      goalv1beta1.GoalParameters
+		policies    xpv1.ManagementPolicies
+		wantID      string
+		wantGoals   []string
+		wantDrifted bool
+		wantReason  xpv1.ConditionReason
+	}{
+		"EventNameChanged": {
+			existing: clients.Goal{ID: "goal-1", GoalType: "event", EventName: "signup"},
+			params: goalv1beta1.GoalParameters{
+				GoalType:  "event",
+				EventName: stringPtr("register"),
+			},
+			wantID:    "goal-2",
+			wantGoals: []string{"goal-2"},
+		},
+		"CurrencyChanged": {
+			existing: clients.Goal{ID: "goal-1", GoalType: "event", EventName: "Purchase", Currency: "USD"},
+			params: goalv1beta1.GoalParameters{
+				GoalType:  "event",
+				EventName: stringPtr("Purchase"),
+				Currency:  stringPtr("EUR"),
+			},
+			wantID:    "goal-2",
+			wantGoals: []string{"goal-2"},
+		},
+		"ReplacePolicyNever": {
+			existing: clients.Goal{ID: "goal-1", GoalType: "event", EventName: "signup"},
+			params: goalv1beta1.GoalParameters{
+				GoalType:      "event",
+				EventName:     stringPtr("register"),
+				ReplacePolicy: goalv1beta1.ReplacePolicyNever,
+			},
+			wantID:      "goal-1",
+			wantGoals:   []string{"goal-1"},
+			wantDrifted: true,
+			wantReason:  reasonReplacePolicyNever,
+		},
+		"ObserveOnly": {
+			existing: clients.Goal{ID: "goal-1", GoalType: "event", EventName: "signup"},
+			params: goalv1beta1.GoalParameters{
+				GoalType:  "event",
+				EventName: stringPtr("register"),
+			},
+			policies:    xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			wantID:      "goal-1",
+			wantGoals:   []string{"goal-1"},
+			wantDrifted: true,
+			wantReason:  reasonReplaceNotManaged,
+		},
+		"CreateWithoutDelete": {
+			existing: clients.Goal{ID: "goal-1", GoalType: "event", EventName: "signup"},
+			params: goalv1beta1.GoalParameters{
+				GoalType:  "event",
+				EventName: stringPtr("register"),
+			},
+			policies:    xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionCreate, xpv1.ManagementActionUpdate},
+			wantID:      "goal-1",
+			wantGoals:   []string{"goal-1"},
+			wantDrifted: true,
+			wantReason:  reasonReplaceNotManaged,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			goals := map[string]clients.Goal{tc.existing.ID: tc.existing}
+			srv := goalServer(t, goals)
+			defer srv.Close()
+
+			e := &external{service: clients.NewClient(clients.Config{BaseURL: srv.URL, APIKey: "test-key"})}
+			tc.params.SiteDomain = stringPtr("example.com")
+			cr := &goalv1beta1.Goal{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: tc.existing.ID},
+				},
+				Spec: goalv1beta1.GoalSpec{ForProvider: tc.params},
+			}
+			cr.SetManagementPolicies(tc.policies)
+			ctx := context.Background()
+
+			o, err := e.Observe(ctx, cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if o.ResourceExists != tc.wantDrifted || o.ResourceUpToDate != tc.wantDrifted {
+				t.Fatalf("Observe(...): got %+v, want existing and up to date %t", o, tc.wantDrifted)
+			}
+			c := cr.GetCondition(clients.TypeDrifted)
+			if drifted := c.Status == corev1.ConditionTrue; drifted != tc.wantDrifted {
+				t.Errorf("Drifted condition: got %t, want %t", drifted, tc.wantDrifted)
+			}
+			if tc.wantDrifted && c.Reason != tc.wantReason {
+				t.Errorf("Drifted condition: got reason %q, want %q", c.Reason, tc.wantReason)
+			}
+			if o.ResourceExists {
+				_, err = e.Update(ctx, cr)
+			} else {
+				_, err = e.Create(ctx, cr)
+			}
+			if err != nil {
+				t.Fatalf("replacing goal: unexpected error: %v", err)
+			}
+
+			if got := meta.GetExternalName(cr); got != tc.wantID {
+				t.Errorf("external name: got %q, want %q", got, tc.wantID)
+			}
+			var got []string
+			for id := range goals {
+				got = append(got, id)
+			}
+			if diff := cmp.Diff(tc.wantGoals, got); diff != "" {
+				t.Errorf("goals in Plausible: -want, +got:\n%s", diff)
+			}
+
+			if tc.wantDrifted {
+				return
+			}
+			o, err = e.Observe(ctx, cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if !o.ResourceExists || !o.ResourceUpToDate {
+				t.Errorf("Observe(...) after replacing: got %+v, want existing and up to date", o)
+			}
+		})
+	}
+}
+
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
 }
+
+func intPtr(i int) *int {
+	return &i
+}
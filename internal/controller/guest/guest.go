@@ -0,0 +1,361 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	guestv1beta1 "github.com/rossigee/provider-plausible/apis/guest/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/tracing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotGuest = "managed resource is not a Guest custom resource"
+
+	errNoSiteDomain   = "no site domain specified"
+	errDeleteReplaced = "failed to remove guest with the previous email"
+)
+
+const (
+	// statusExpired is the invitation status Plausible reports once an
+	// invitation can no longer be accepted.
+	statusExpired = "expired"
+
+	// defaultRole is the role Plausible assigns when none is requested.
+	defaultRole = "viewer"
+)
+
+// Setup adds a controller that reconciles Guest managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(guestv1beta1.GuestGroupKind.String())
+	recorder := clients.NewEventRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(guestv1beta1.GuestGroupVersionKind),
+		managed.WithExternalConnector(&connector{
+			kube:         mgr.GetClient(),
+			usage:        clients.NewProviderConfigUsageTracker(mgr.GetClient()),
+			newServiceFn: clients.NewClient,
+			recorder:     recorder,
+		}),
+		// The external name is the email of the guest, which is set in the
+		// spec, so it must not default to the name of the Kubernetes object.
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithReferenceResolver(clients.NewSiteDomainReferenceResolver(mgr.GetClient(), forgetSiteDomain, unselectSite)))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&guestv1beta1.Guest{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// forgetSiteDomain clears the site domain of a Guest that references or selects its Site,
+// so that it is resolved again and follows the Site if it is renamed.
+func forgetSiteDomain(mg resource.Managed) {
+	if cr, ok := mg.(*guestv1beta1.Guest); ok && (cr.Spec.ForProvider.SiteDomainRef != nil || cr.Spec.ForProvider.SiteDomainSelector != nil) {
+		cr.Spec.ForProvider.SiteDomain = nil
+	}
+}
+
+// unselectSite clears the Site reference a siteDomainSelector resolved to, so
+// that the Guest keeps only the selector and is validated as it was applied.
+func unselectSite(mg resource.Managed) {
+	if cr, ok := mg.(*guestv1beta1.Guest); ok && cr.Spec.ForProvider.SiteDomainSelector != nil {
+		cr.Spec.ForProvider.SiteDomainRef = nil
+	}
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(config clients.Config) *clients.Client
+	recorder     event.Recorder
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*guestv1beta1.Guest)
+	if !ok {
+		return nil, errors.New(errNotGuest)
+	}
+
+	cfg, err := clients.GetConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := c.newServiceFn(*cfg)
+
+	return clients.WithEvents(clients.WithErrorConditions(&external{service: svc, kube: c.kube}), c.recorder), nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	service *clients.Client
+	kube    client.Client
+}
+
+// getSiteDomain returns the domain of the site the guest belongs to. Any
+// siteDomainRef or siteDomainSelector has already been resolved into
+// siteDomain by the time the managed reconciler calls the external client.
+func getSiteDomain(cr *guestv1beta1.Guest) (string, error) {
+	if cr.Spec.ForProvider.SiteDomain == nil || *cr.Spec.ForProvider.SiteDomain == "" {
+		return "", errors.New(errNoSiteDomain)
+	}
+	return *cr.Spec.ForProvider.SiteDomain, nil
+}
+
+// guestEmail returns the email of the guest that was last invited or adopted
+// for cr, or the email from the spec if there is none yet.
+func guestEmail(cr *guestv1beta1.Guest) string {
+	if en := meta.GetExternalName(cr); en != "" {
+		return en
+	}
+	return cr.Spec.ForProvider.Email
+}
+
+// emailChanged returns true if the email changed in the spec since the guest
+// was invited. A different email is a different guest, so the guest is
+// replaced unless cr is being deleted.
+func emailChanged(cr *guestv1beta1.Guest) bool {
+	return cr.GetDeletionTimestamp() == nil && guestEmail(cr) != cr.Spec.ForProvider.Email
+}
+
+// desiredRole returns the role requested in the spec, applying the default.
+func desiredRole(cr *guestv1beta1.Guest) string {
+	if cr.Spec.ForProvider.Role == "" {
+		return defaultRole
+	}
+	return cr.Spec.ForProvider.Role
+}
+
+// parseTime converts a Plausible timestamp into a metav1.Time. Plausible
+// returns ISO 8601 timestamps with or without a zone designator.
+func parseTime(s string) *metav1.Time {
+	if s == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			mt := metav1.NewTime(t)
+			return &mt
+		}
+	}
+	return nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*guestv1beta1.Guest)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotGuest)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "guest.observe", "Guest", cr.GetName(), "observe")
+	defer span.End()
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	if emailChanged(cr) {
+		// Report the guest as missing so that Create replaces it. Unlike
+		// Update, Create can change the external name.
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+
+	guest, err := c.service.GetGuest(ctx, siteDomain, guestEmail(cr))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to get guest")
+	}
+
+	if guest == nil {
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+
+	meta.SetExternalName(cr, guest.Email)
+
+	cr.Status.AtProvider = guestv1beta1.GuestObservation{
+		Email:      guest.Email,
+		Role:       guest.Role,
+		Status:     guest.Status,
+		InvitedAt:  parseTime(guest.InvitedAt),
+		AcceptedAt: parseTime(guest.AcceptedAt),
+	}
+
+	// An expired invitation will never grant access, so it is reported as
+	// unavailable until Update re-issues it.
+	if guest.Status == statusExpired {
+		cr.SetConditions(xpv1.Unavailable())
+	} else {
+		cr.SetConditions(xpv1.Available())
+	}
+
+	// Earlier versions reported a role change of a guest who accepted as
+	// drift instead of applying it.
+	clients.SetDrifted(cr, false, "", "")
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: c.isUpToDate(cr, guest),
+	}, nil
+}
+
+// isUpToDate returns false if the invitation of guest has to be issued again,
+// because it expired or was issued for a different role.
+func (c *external) isUpToDate(cr *guestv1beta1.Guest, guest *clients.Guest) bool {
+	if guest.Status == statusExpired {
+		return false
+	}
+
+	return desiredRole(cr) == guest.Role
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*guestv1beta1.Guest)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotGuest)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "guest.create", "Guest", cr.GetName(), "create")
+	defer span.End()
+
+	cr.SetConditions(xpv1.Creating())
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	// Observe reports a guest whose email changed in the spec as missing, so
+	// the guest is replaced here.
+	var replaced string
+	if emailChanged(cr) {
+		replaced = guestEmail(cr)
+	}
+
+	if err := c.invite(ctx, siteDomain, cr); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, "failed to invite guest")
+	}
+
+	if replaced != "" {
+		err := c.service.DeleteGuest(ctx, siteDomain, replaced)
+		if err != nil && !clients.IsNotFound(err) {
+			return managed.ExternalCreation{}, errors.Wrap(err, errDeleteReplaced)
+		}
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) invite(ctx context.Context, siteDomain string, cr *guestv1beta1.Guest) error {
+	guest, err := c.service.CreateGuest(ctx, clients.CreateGuestRequest{
+		SiteDomain: siteDomain,
+		Email:      cr.Spec.ForProvider.Email,
+		Role:       desiredRole(cr),
+	})
+	if err != nil {
+		return err
+	}
+
+	meta.SetExternalName(cr, guest.Email)
+
+	return nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*guestv1beta1.Guest)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotGuest)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "guest.update", "Guest", cr.GetName(), "update")
+	defer span.End()
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// The Guests API has no way to change a role or renew an invitation in
+	// place, so the guest is removed and invited again. A guest who had
+	// accepted loses access until they accept the new invitation.
+	err = c.service.DeleteGuest(ctx, siteDomain, guestEmail(cr))
+	if err != nil && !clients.IsNotFound(err) {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to remove guest")
+	}
+
+	if err := c.invite(ctx, siteDomain, cr); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to re-invite guest")
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*guestv1beta1.Guest)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotGuest)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "guest.delete", "Guest", cr.GetName(), "delete")
+	defer span.End()
+
+	cr.SetConditions(xpv1.Deleting())
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+
+	err = c.service.DeleteGuest(ctx, siteDomain, guestEmail(cr))
+	if err != nil && !clients.IsNotFound(err) {
+		return managed.ExternalDelete{}, errors.Wrap(err, "failed to remove guest")
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	// Nothing to disconnect for Plausible API client
+	return nil
+}
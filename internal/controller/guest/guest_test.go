@@ -0,0 +1,316 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	guestv1beta1 "github.com/rossigee/provider-plausible/apis/guest/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/clients/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newExternal(t *testing.T, f *fake.Plausible) *external {
+	t.Helper()
+	return &external{service: f.NewClient(t)}
+}
+
+func guest(email, role, externalName string) *guestv1beta1.Guest {
+	cr := &guestv1beta1.Guest{
+		ObjectMeta: metav1.ObjectMeta{Name: "agency"},
+		Spec: guestv1beta1.GuestSpec{
+			ForProvider: guestv1beta1.GuestParameters{
+				SiteDomain: stringPtr("example.com"),
+				Email:      email,
+				Role:       role,
+			},
+		},
+	}
+	if externalName != "" {
+		meta.SetExternalName(cr, externalName)
+	}
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	invited := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	accepted := metav1.NewTime(time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC))
+
+	cases := map[string]struct {
+		guests  map[string]clients.Guest
+		cr      *guestv1beta1.Guest
+		want    managed.ExternalObservation
+		obs     guestv1beta1.GuestObservation
+		cond    xpv1.Condition
+		drifted bool
+	}{
+		"NotFound": {
+			guests: map[string]clients.Guest{},
+			cr:     guest("a@example.com", "viewer", ""),
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"Accepted": {
+			guests: map[string]clients.Guest{
+				"a@example.com": {Email: "a@example.com", Role: "viewer", Status: "accepted", InvitedAt: "2025-01-01T00:00:00Z", AcceptedAt: "2025-01-02T09:30:00"},
+			},
+			cr:   guest("a@example.com", "", ""),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			obs: guestv1beta1.GuestObservation{
+				Email: "a@example.com", Role: "viewer", Status: "accepted", InvitedAt: &invited, AcceptedAt: &accepted,
+			},
+			cond: xpv1.Available(),
+		},
+		"AdoptedBySpecEmail": {
+			// The Guest is named agency, but manages the guest with the email
+			// from its spec, which may already have been invited.
+			guests: map[string]clients.Guest{
+				"a@example.com": {Email: "a@example.com", Role: "viewer", Status: "accepted"},
+				"b@example.com": {Email: "b@example.com", Role: "admin", Status: "accepted"},
+			},
+			cr:   guest("a@example.com", "viewer", ""),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			obs:  guestv1beta1.GuestObservation{Email: "a@example.com", Role: "viewer", Status: "accepted"},
+			cond: xpv1.Available(),
+		},
+		"RoleChangedPending": {
+			guests: map[string]clients.Guest{
+				"a@example.com": {Email: "a@example.com", Role: "viewer", Status: "pending"},
+			},
+			cr:   guest("a@example.com", "admin", "a@example.com"),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			obs:  guestv1beta1.GuestObservation{Email: "a@example.com", Role: "viewer", Status: "pending"},
+			cond: xpv1.Available(),
+		},
+		"RoleChangedAccepted": {
+			guests: map[string]clients.Guest{
+				"a@example.com": {Email: "a@example.com", Role: "viewer", Status: "accepted"},
+			},
+			cr: func() *guestv1beta1.Guest {
+				// Earlier versions reported this as drift.
+				cr := guest("a@example.com", "admin", "a@example.com")
+				clients.SetDrifted(cr, true, "RoleNotReissued", "")
+				return cr
+			}(),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			obs:  guestv1beta1.GuestObservation{Email: "a@example.com", Role: "viewer", Status: "accepted"},
+			cond: xpv1.Available(),
+		},
+		"EmailChanged": {
+			guests: map[string]clients.Guest{
+				"b@example.com": {Email: "b@example.com", Role: "viewer", Status: "accepted"},
+			},
+			cr:   guest("a@example.com", "viewer", "b@example.com"),
+			want: managed.ExternalObservation{ResourceExists: false},
+		},
+		"Expired": {
+			guests: map[string]clients.Guest{
+				"a@example.com": {Email: "a@example.com", Role: "viewer", Status: "expired", InvitedAt: "2025-01-01T00:00:00Z"},
+			},
+			cr:   guest("a@example.com", "viewer", "a@example.com"),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			obs:  guestv1beta1.GuestObservation{Email: "a@example.com", Role: "viewer", Status: "expired", InvitedAt: &invited},
+			cond: xpv1.Unavailable(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, &fake.Plausible{Guests: tc.guests})
+
+			got, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.obs, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("Observe(...): atProvider -want, +got:\n%s", diff)
+			}
+			if !tc.want.ResourceExists {
+				return
+			}
+			if got := tc.cr.GetCondition(xpv1.TypeReady); !got.Equal(tc.cond) {
+				t.Errorf("Observe(...): Ready condition = %v, want %v", got, tc.cond)
+			}
+			if drifted := tc.cr.GetCondition(clients.TypeDrifted).Status == corev1.ConditionTrue; drifted != tc.drifted {
+				t.Errorf("Observe(...): Drifted = %t, want %t", drifted, tc.drifted)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		guests      map[string]clients.Guest
+		cr          *guestv1beta1.Guest
+		wantGuests  []string
+		wantDeleted []string
+	}{
+		"Invited": {
+			cr:         guest("a@example.com", "", ""),
+			wantGuests: []string{"a@example.com"},
+		},
+		"EmailChanged": {
+			guests: map[string]clients.Guest{
+				"b@example.com": {Email: "b@example.com", Role: "viewer", Status: "accepted"},
+			},
+			cr:          guest("a@example.com", "", "b@example.com"),
+			wantGuests:  []string{"a@example.com"},
+			wantDeleted: []string{"b@example.com"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fake.Plausible{Guests: tc.guests}
+			e := newExternal(t, f)
+
+			if _, err := e.Create(context.Background(), tc.cr); err != nil {
+				t.Fatalf("Create(...): unexpected error: %v", err)
+			}
+			if got := f.Guests["a@example.com"].Role; got != defaultRole {
+				t.Errorf("Create(...): role = %q, want %q", got, defaultRole)
+			}
+			if en := meta.GetExternalName(tc.cr); en != "a@example.com" {
+				t.Errorf("Create(...): external name = %q, want %q", en, "a@example.com")
+			}
+			if diff := cmp.Diff(tc.wantGuests, slices.Sorted(maps.Keys(f.Guests))); diff != "" {
+				t.Errorf("Create(...): guests -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, f.Deleted); diff != "" {
+				t.Errorf("Create(...): deleted -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	reinvited := clients.Guest{Email: "a@example.com", Role: "admin", Status: "pending", InvitedAt: "2025-01-01T00:00:00Z"}
+
+	cases := map[string]struct {
+		existing    clients.Guest
+		want        clients.Guest
+		wantDeleted []string
+	}{
+		"Expired": {
+			existing:    clients.Guest{Email: "a@example.com", Role: "viewer", Status: "expired"},
+			want:        reinvited,
+			wantDeleted: []string{"a@example.com"},
+		},
+		"Pending": {
+			existing:    clients.Guest{Email: "a@example.com", Role: "viewer", Status: "pending"},
+			want:        reinvited,
+			wantDeleted: []string{"a@example.com"},
+		},
+		"Accepted": {
+			existing:    clients.Guest{Email: "a@example.com", Role: "viewer", Status: "accepted"},
+			want:        reinvited,
+			wantDeleted: []string{"a@example.com"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fake.Plausible{Guests: map[string]clients.Guest{tc.existing.Email: tc.existing}}
+			e := newExternal(t, f)
+			cr := guest("a@example.com", "admin", "a@example.com")
+			cr.Status.AtProvider.Status = tc.existing.Status
+
+			if _, err := e.Update(context.Background(), cr); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, f.Deleted); diff != "" {
+				t.Errorf("Update(...): deleted -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, f.Guests["a@example.com"]); diff != "" {
+				t.Errorf("Update(...): guest -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestChangeEmail changes the email of a Guest and checks that it converges,
+// although the managed reconciler keeps no external name set by Update.
+func TestChangeEmail(t *testing.T) {
+	f := &fake.Plausible{}
+	e := newExternal(t, f)
+	cr := guest("b@example.com", "viewer", "")
+	ctx := context.Background()
+
+	if err := fake.Reconcile(ctx, e, cr); err != nil {
+		t.Fatalf("Reconcile(...): unexpected error: %v", err)
+	}
+	cr.Spec.ForProvider.Email = "a@example.com"
+	for range 2 {
+		if err := fake.Reconcile(ctx, e, cr); err != nil {
+			t.Fatalf("Reconcile(...): unexpected error: %v", err)
+		}
+	}
+
+	if en := meta.GetExternalName(cr); en != "a@example.com" {
+		t.Errorf("external name = %q, want %q", en, "a@example.com")
+	}
+	if diff := cmp.Diff([]string{"a@example.com"}, slices.Sorted(maps.Keys(f.Guests))); diff != "" {
+		t.Errorf("guests -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"b@example.com"}, f.Deleted); diff != "" {
+		t.Errorf("deleted -want, +got:\n%s", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		guests map[string]clients.Guest
+	}{
+		"Successful": {
+			guests: map[string]clients.Guest{"a@example.com": {Email: "a@example.com"}},
+		},
+		"AlreadyDeleted": {
+			guests: map[string]clients.Guest{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fake.Plausible{Guests: tc.guests}
+			e := newExternal(t, f)
+
+			if _, err := e.Delete(context.Background(), guest("a@example.com", "viewer", "a@example.com")); err != nil {
+				t.Errorf("Delete(...): unexpected error: %v", err)
+			}
+			if _, ok := f.Guests["a@example.com"]; ok {
+				t.Errorf("Delete(...): expected guest to be removed")
+			}
+		})
+	}
+}
+
+// Helper function to create string pointers
+func stringPtr(s string) *string {
+	return &s
+}
@@ -28,46 +28,108 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 
+	namespacedv1beta1 "github.com/rossigee/provider-plausible/apis/namespaced/v1beta1"
 	"github.com/rossigee/provider-plausible/apis/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
 )
 
-const controllerName = "providerconfig.plausible.crossplane.io"
+const (
+	controllerName           = "providerconfig.plausible.crossplane.io"
+	namespacedControllerName = "providerconfig.plausible.m.crossplane.io"
+	clusterControllerName    = "clusterproviderconfig.plausible.crossplane.io"
 
-// Setup registers the ProviderConfig controller.
+	// recheckInterval is how often credentials are validated again, so that
+	// revoked or rotated keys show up on the ProviderConfig before managed
+	// resources start failing.
+	recheckInterval = 10 * time.Minute
+)
+
+// Reasons a ProviderConfig may be unavailable.
+const (
+	ReasonInvalidCredentials xpv1.ConditionReason = "InvalidCredentials"
+	ReasonSitesAPIDisabled   xpv1.ConditionReason = "SitesAPIDisabled"
+	ReasonUnreachable        xpv1.ConditionReason = "Unreachable"
+	ReasonCheckFailed        xpv1.ConditionReason = "CheckFailed"
+)
+
+// providerConfig is satisfied by every ProviderConfig and ClusterProviderConfig.
+type providerConfig interface {
+	client.Object
+	SetConditions(c ...xpv1.Condition)
+}
+
+// Setup registers the ProviderConfig and ClusterProviderConfig controllers. The
+// cluster scoped ProviderConfig of the plausible.crossplane.io group predates
+// the other kinds and is still served for existing installations.
 func Setup(mgr ctrl.Manager) error {
-	r := &reconciler{kube: mgr.GetClient(), logger: mgr.GetLogger()}
-	return ctrl.NewControllerManagedBy(mgr).
+	r := &reconciler{
+		kube:         mgr.GetClient(),
+		kind:         v1beta1.ProviderConfigKind,
+		logger:       mgr.GetLogger().WithValues("kind", v1beta1.ProviderConfigKind),
+		newConfig:    func() providerConfig { return &v1beta1.ProviderConfig{} },
+		newServiceFn: clients.NewClient,
+	}
+	if err := ctrl.NewControllerManagedBy(mgr).
 		Named(controllerName).
 		For(&v1beta1.ProviderConfig{}).
-		Complete(r)
+		Complete(r); err != nil {
+		return err
+	}
+
+	nr := &reconciler{
+		kube:         mgr.GetClient(),
+		kind:         namespacedv1beta1.ProviderConfigKind,
+		logger:       mgr.GetLogger().WithValues("kind", namespacedv1beta1.ProviderConfigKind, "group", namespacedv1beta1.Group),
+		newConfig:    func() providerConfig { return &namespacedv1beta1.ProviderConfig{} },
+		newServiceFn: clients.NewClient,
+	}
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named(namespacedControllerName).
+		For(&namespacedv1beta1.ProviderConfig{}).
+		Complete(nr); err != nil {
+		return err
+	}
+
+	cr := &reconciler{
+		kube:         mgr.GetClient(),
+		kind:         v1beta1.ClusterProviderConfigKind,
+		logger:       mgr.GetLogger().WithValues("kind", v1beta1.ClusterProviderConfigKind),
+		newConfig:    func() providerConfig { return &v1beta1.ClusterProviderConfig{} },
+		newServiceFn: clients.NewClient,
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(clusterControllerName).
+		For(&v1beta1.ClusterProviderConfig{}).
+		Complete(cr)
 }
 
 type reconciler struct {
-	kube   client.Client
-	logger logr.Logger
+	kube         client.Client
+	kind         string
+	logger       logr.Logger
+	newConfig    func() providerConfig
+	newServiceFn func(config clients.Config) *clients.Client
 }
 
 func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
-	log := r.logger.WithValues("providerconfig", req.Name)
+	log := r.logger.WithValues("providerconfig", req.NamespacedName)
 	log.Info("reconciling ProviderConfig")
 
-	pc := &v1beta1.ProviderConfig{}
+	pc := r.newConfig()
 	if err := r.kube.Get(ctx, req.NamespacedName, pc); err != nil {
 		log.Error(err, "failed to get ProviderConfig")
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
-	log.Info("ProviderConfig available")
-	pc.Status.SetConditions(xpv1.Available())
-
-	fresh := &v1beta1.ProviderConfig{}
-	if err := r.kube.Get(ctx, client.ObjectKey{Name: pc.GetName()}, fresh); err != nil {
-		log.Error(err, "failed to get latest ProviderConfig for status update")
-		return reconcile.Result{RequeueAfter: 30 * time.Second}, client.IgnoreNotFound(err)
+	cond := r.check(ctx, pc)
+	if cond.Reason == xpv1.ReasonAvailable {
+		log.Info("ProviderConfig available")
+	} else {
+		log.Info("ProviderConfig unavailable", "reason", cond.Reason, "message", cond.Message)
 	}
+	pc.SetConditions(cond)
 
-	fresh.Status = pc.Status
-	if err := r.kube.Status().Update(ctx, fresh); err != nil {
+	if err := r.kube.Status().Update(ctx, pc); err != nil {
 		log.Error(err, "failed to update ProviderConfig status")
 		if errors.IsConflict(err) {
 			log.Info("conflict updating, will retry")
@@ -76,5 +138,46 @@ func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: recheckInterval}, nil
+}
+
+// check extracts the credentials of the supplied ProviderConfig and makes a
+// cheap authenticated request with them, returning the resulting Ready
+// condition.
+func (r *reconciler) check(ctx context.Context, pc providerConfig) xpv1.Condition {
+	spec, err := clients.EffectiveSpec(pc)
+	if err != nil {
+		return unavailable(ReasonCheckFailed, err.Error())
+	}
+
+	cfg, err := clients.ConfigFromSpec(ctx, r.kube, spec)
+	if err != nil {
+		return unavailable(ReasonInvalidCredentials, err.Error())
+	}
+	if cfg.APIKey == "" {
+		return unavailable(ReasonInvalidCredentials, "credentials do not contain an apiKey")
+	}
+	// Share the token bucket of the resources using this ProviderConfig, so
+	// that health checks count against the same quota.
+	cfg.ProviderConfigKey = clients.ProviderConfigKey(r.kind, pc.GetNamespace(), pc.GetName())
+
+	err = r.newServiceFn(*cfg).CheckCredentials(ctx)
+	switch {
+	case err == nil:
+		return xpv1.Available()
+	case clients.IsUnauthorized(err):
+		return unavailable(ReasonInvalidCredentials, "the API key was rejected by "+cfg.BaseURL)
+	case clients.IsForbidden(err):
+		return unavailable(ReasonSitesAPIDisabled, "the API key does not have access to the Sites API; ask Plausible to enable it for your team")
+	case clients.IsUnreachable(err):
+		return unavailable(ReasonUnreachable, err.Error())
+	default:
+		return unavailable(ReasonCheckFailed, err.Error())
+	}
+}
+
+func unavailable(reason xpv1.ConditionReason, msg string) xpv1.Condition {
+	c := xpv1.Unavailable().WithMessage(msg)
+	c.Reason = reason
+	return c
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-plausible/apis/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+)
+
+const envCredentials = "PLAUSIBLE_TEST_CREDENTIALS"
+
+func newProviderConfig(baseURL string) *v1beta1.ClusterProviderConfig {
+	noRetries := 0
+	return &v1beta1.ClusterProviderConfig{
+		Spec: v1beta1.ProviderConfigSpec{
+			BaseURL:   &baseURL,
+			Transport: &v1beta1.TransportConfig{MaxRetries: &noRetries},
+			Credentials: v1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceEnvironment,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					Env: &xpv1.EnvSelector{Name: envCredentials},
+				},
+			},
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cases := map[string]struct {
+		creds   string
+		status  int
+		closed  bool
+		reason  xpv1.ConditionReason
+		wantAPI bool
+	}{
+		"Valid": {
+			creds:   `{"apiKey":"good"}`,
+			status:  http.StatusOK,
+			reason:  xpv1.ReasonAvailable,
+			wantAPI: true,
+		},
+		"MalformedCredentials": {
+			creds:  `not-json`,
+			reason: ReasonInvalidCredentials,
+		},
+		"MissingAPIKey": {
+			creds:  `{}`,
+			reason: ReasonInvalidCredentials,
+		},
+		"RejectedKey": {
+			creds:   `{"apiKey":"bad"}`,
+			status:  http.StatusUnauthorized,
+			reason:  ReasonInvalidCredentials,
+			wantAPI: true,
+		},
+		"SitesAPIDisabled": {
+			creds:   `{"apiKey":"good"}`,
+			status:  http.StatusPaymentRequired,
+			reason:  ReasonSitesAPIDisabled,
+			wantAPI: true,
+		},
+		"ServerError": {
+			creds:   `{"apiKey":"good"}`,
+			status:  http.StatusInternalServerError,
+			reason:  ReasonCheckFailed,
+			wantAPI: true,
+		},
+		"Unreachable": {
+			creds:  `{"apiKey":"good"}`,
+			closed: true,
+			reason: ReasonUnreachable,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(envCredentials, tc.creds)
+
+			called := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				if r.URL.Path != "/api/v1/sites" || r.URL.Query().Get("limit") != "1" {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(`{"sites":[],"meta":{}}`))
+			}))
+			if tc.closed {
+				srv.Close()
+			} else {
+				defer srv.Close()
+			}
+
+			r := &reconciler{newServiceFn: clients.NewClient}
+			got := r.check(context.Background(), newProviderConfig(srv.URL))
+
+			if got.Type != xpv1.TypeReady {
+				t.Errorf("check(...): condition type = %q, want %q", got.Type, xpv1.TypeReady)
+			}
+			if got.Reason != tc.reason {
+				t.Errorf("check(...): reason = %q, want %q (message %q)", got.Reason, tc.reason, got.Message)
+			}
+			if called != tc.wantAPI {
+				t.Errorf("check(...): API called = %t, want %t", called, tc.wantAPI)
+			}
+		})
+	}
+}
@@ -0,0 +1,511 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharedlink
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	sharedlinkv1beta1 "github.com/rossigee/provider-plausible/apis/sharedlink/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errNotSharedLink = "managed resource is not a SharedLink custom resource"
+
+	errNoSiteDomain        = "no site domain specified"
+	errGetPasswordSecret   = "cannot get password secret"
+	errNoPasswordKey       = "password secret %s has no key %q"
+	errNoConnectionSecret  = "generatePassword requires writeConnectionSecretToRef"
+	errGetConnection       = "cannot get connection secret"
+	errGeneratePassword    = "cannot generate password"
+	errDeleteRenamed       = "failed to delete renamed shared link"
+	errIndexPasswordSecret = "cannot index shared links by password secret"
+)
+
+// generatedPasswordBytes is the number of random bytes in a generated
+// password, which is published base64 encoded.
+const generatedPasswordBytes = 24
+
+// Connection detail keys published for a SharedLink.
+const (
+	keyURL      = "url"
+	keyPassword = "password"
+)
+
+// Setup adds a controller that reconciles SharedLink managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(sharedlinkv1beta1.SharedLinkGroupKind.String())
+	recorder := clients.NewEventRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(sharedlinkv1beta1.SharedLinkGroupVersionKind),
+		managed.WithExternalConnector(&connector{
+			kube:         mgr.GetClient(),
+			usage:        clients.NewProviderConfigUsageTracker(mgr.GetClient()),
+			newServiceFn: clients.NewClient,
+			recorder:     recorder,
+		}),
+		// The external name is the name of the link in Plausible, which is
+		// set in the spec, so it must not default to the name of the
+		// Kubernetes object.
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithReferenceResolver(clients.NewSiteDomainReferenceResolver(mgr.GetClient(), forgetSiteDomain, unselectSite)))
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &sharedlinkv1beta1.SharedLink{}, passwordSecretIndex, passwordSecretName); err != nil {
+		return errors.Wrap(err, errIndexPasswordSecret)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&sharedlinkv1beta1.SharedLink{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(passwordSecretUsers(mgr.GetClient()))).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// passwordSecretIndex indexes SharedLinks by the name of the Secret that
+// holds their password.
+const passwordSecretIndex = "spec.forProvider.passwordSecretRef.name"
+
+// passwordSecretName returns the name of the Secret that holds the password
+// of a SharedLink, if any.
+func passwordSecretName(obj client.Object) []string {
+	cr, ok := obj.(*sharedlinkv1beta1.SharedLink)
+	if !ok || cr.Spec.ForProvider.PasswordSecretRef == nil {
+		return nil
+	}
+	return []string{cr.Spec.ForProvider.PasswordSecretRef.Name}
+}
+
+// passwordSecretUsers maps a Secret to the SharedLinks in its namespace whose
+// password it holds, so that a new password is applied without waiting for
+// the next poll.
+func passwordSecretUsers(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		l := &sharedlinkv1beta1.SharedLinkList{}
+		if err := kube.List(ctx, l, client.InNamespace(obj.GetNamespace()), client.MatchingFields{passwordSecretIndex: obj.GetName()}); err != nil {
+			return nil
+		}
+		reqs := make([]reconcile.Request, 0, len(l.Items))
+		for _, cr := range l.Items {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()}})
+		}
+		return reqs
+	}
+}
+
+// forgetSiteDomain clears the site domain of a SharedLink that references or selects its Site,
+// so that it is resolved again and follows the Site if it is renamed.
+func forgetSiteDomain(mg resource.Managed) {
+	if cr, ok := mg.(*sharedlinkv1beta1.SharedLink); ok && (cr.Spec.ForProvider.SiteDomainRef != nil || cr.Spec.ForProvider.SiteDomainSelector != nil) {
+		cr.Spec.ForProvider.SiteDomain = nil
+	}
+}
+
+// unselectSite clears the Site reference a siteDomainSelector resolved to, so
+// that the SharedLink keeps only the selector and is validated as it was applied.
+func unselectSite(mg resource.Managed) {
+	if cr, ok := mg.(*sharedlinkv1beta1.SharedLink); ok && cr.Spec.ForProvider.SiteDomainSelector != nil {
+		cr.Spec.ForProvider.SiteDomainRef = nil
+	}
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(config clients.Config) *clients.Client
+	recorder     event.Recorder
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*sharedlinkv1beta1.SharedLink)
+	if !ok {
+		return nil, errors.New(errNotSharedLink)
+	}
+
+	cfg, err := clients.GetConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := c.newServiceFn(*cfg)
+
+	return clients.WithEvents(clients.WithErrorConditions(&external{service: svc, kube: c.kube}), c.recorder), nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	service *clients.Client
+	kube    client.Client
+}
+
+// getSiteDomain returns the domain of the site the shared link belongs to. Any
+// siteDomainRef or siteDomainSelector has already been resolved into
+// siteDomain by the time the managed reconciler calls the external client.
+func getSiteDomain(cr *sharedlinkv1beta1.SharedLink) (string, error) {
+	if cr.Spec.ForProvider.SiteDomain == nil || *cr.Spec.ForProvider.SiteDomain == "" {
+		return "", errors.New(errNoSiteDomain)
+	}
+	return *cr.Spec.ForProvider.SiteDomain, nil
+}
+
+// linkName returns the name of the shared link that was last created or
+// adopted for cr, or the name from the spec if there is none yet.
+func linkName(cr *sharedlinkv1beta1.SharedLink) string {
+	if en := meta.GetExternalName(cr); en != "" {
+		return en
+	}
+	return cr.Spec.ForProvider.Name
+}
+
+// renamed returns true if the name of the shared link changed in the spec
+// since it was created. Shared links cannot be renamed in Plausible, so the
+// link is replaced unless cr is being deleted.
+func renamed(cr *sharedlinkv1beta1.SharedLink) bool {
+	return cr.GetDeletionTimestamp() == nil && linkName(cr) != cr.Spec.ForProvider.Name
+}
+
+// password returns the password the shared link should be protected with,
+// or an empty string if it should not be protected. A password from
+// passwordSecretRef wins over a generated one, which wins over the
+// deprecated inline password.
+func (c *external) password(ctx context.Context, cr *sharedlinkv1beta1.SharedLink) (string, error) {
+	p := cr.Spec.ForProvider
+	switch {
+	case p.PasswordSecretRef != nil:
+		s := &corev1.Secret{}
+		nn := types.NamespacedName{Namespace: cr.GetNamespace(), Name: p.PasswordSecretRef.Name}
+		if err := c.kube.Get(ctx, nn, s); err != nil {
+			return "", errors.Wrap(err, errGetPasswordSecret)
+		}
+		v, ok := s.Data[p.PasswordSecretRef.Key]
+		if !ok || len(v) == 0 {
+			return "", errors.Errorf(errNoPasswordKey, nn, p.PasswordSecretRef.Key)
+		}
+		return string(v), nil
+
+	case p.GeneratePassword != nil && *p.GeneratePassword:
+		if cr.GetWriteConnectionSecretToReference() == nil {
+			return "", errors.New(errNoConnectionSecret)
+		}
+		// Reuse the password published by an earlier reconcile, so that it
+		// is only generated once.
+		v, err := c.publishedPassword(ctx, cr)
+		if err != nil || v != "" {
+			return v, err
+		}
+		return generatePassword()
+
+	case p.Password != nil:
+		return *p.Password, nil
+	}
+	return "", nil
+}
+
+// generatePassword returns a random, URL safe password.
+func generatePassword() (string, error) {
+	b := make([]byte, generatedPasswordBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, errGeneratePassword)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// publishedPassword returns the password published in the connection Secret
+// of cr, which is the one the shared link was last created with. It returns an
+// empty string if cr has no connection Secret or nothing was published yet.
+func (c *external) publishedPassword(ctx context.Context, cr *sharedlinkv1beta1.SharedLink) (string, error) {
+	ref := cr.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return "", nil
+	}
+	s := &corev1.Secret{}
+	err := c.kube.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: ref.Name}, s)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return "", errors.Wrap(err, errGetConnection)
+	}
+	return string(s.Data[keyPassword]), nil
+}
+
+func connectionDetails(link *clients.SharedLink, password string) managed.ConnectionDetails {
+	cd := managed.ConnectionDetails{
+		keyURL: []byte(link.URL),
+	}
+	if password != "" {
+		cd[keyPassword] = []byte(password)
+	}
+	return cd
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*sharedlinkv1beta1.SharedLink)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSharedLink)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "sharedlink.observe", "SharedLink", cr.GetName(), "observe")
+	defer span.End()
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	if renamed(cr) {
+		// Report the link as missing so that Create replaces it. Unlike
+		// Update, Create can change the external name.
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+
+	link, err := c.service.GetSharedLink(ctx, siteDomain, linkName(cr))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to get shared link")
+	}
+
+	if link == nil {
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+
+	meta.SetExternalName(cr, link.Name)
+
+	// A link that is being deleted only needs to be found. Its password
+	// Secret may already be gone, e.g. when its namespace is deleted.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
+
+	password, err := c.password(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	published, err := c.publishedPassword(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.AtProvider = sharedlinkv1beta1.SharedLinkObservation{
+		Name:        link.Name,
+		URL:         link.URL,
+		HasPassword: link.HasPassword,
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	// The managed reconciler publishes these details before it calls
+	// Update, so a link that is about to be replaced keeps publishing the
+	// password it was created with.
+	upToDate := isUpToDate(cr, link, password, published)
+	if !upToDate {
+		password = published
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  upToDate,
+		ConnectionDetails: connectionDetails(link, password),
+	}, nil
+}
+
+// isUpToDate returns true if link is protected by password, or unprotected if
+// password is empty. The API does not return the password of a link, so it is
+// compared with the published password the link was created with. A protected
+// link without a published password is assumed to use the current one, unless
+// the password is generated: a newly generated password was never sent to
+// Plausible, so the link must be replaced before it is published.
+func isUpToDate(cr *sharedlinkv1beta1.SharedLink, link *clients.SharedLink, password, published string) bool {
+	if (password != "") != link.HasPassword {
+		return false
+	}
+	if password == "" {
+		return true
+	}
+	if published == "" {
+		return !generated(cr)
+	}
+	return published == password
+}
+
+// generated returns true if the password of cr is generated by the provider.
+// A password from passwordSecretRef takes precedence.
+func generated(cr *sharedlinkv1beta1.SharedLink) bool {
+	p := cr.Spec.ForProvider
+	return p.PasswordSecretRef == nil && p.GeneratePassword != nil && *p.GeneratePassword
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*sharedlinkv1beta1.SharedLink)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSharedLink)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "sharedlink.create", "SharedLink", cr.GetName(), "create")
+	defer span.End()
+
+	cr.SetConditions(xpv1.Creating())
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	password, err := c.password(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	// Observe reports a link that was renamed in the spec as missing, so it
+	// is replaced here.
+	var replaced string
+	if renamed(cr) {
+		replaced = linkName(cr)
+	}
+
+	link, err := c.create(ctx, siteDomain, cr, password)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	if replaced != "" {
+		err := c.service.DeleteSharedLink(ctx, siteDomain, replaced)
+		if err != nil && !clients.IsNotFound(err) {
+			return managed.ExternalCreation{}, errors.Wrap(err, errDeleteRenamed)
+		}
+	}
+
+	return managed.ExternalCreation{
+		ConnectionDetails: connectionDetails(link, password),
+	}, nil
+}
+
+func (c *external) create(ctx context.Context, siteDomain string, cr *sharedlinkv1beta1.SharedLink, password string) (*clients.SharedLink, error) {
+	req := clients.CreateSharedLinkRequest{
+		SiteDomain: siteDomain,
+		Name:       cr.Spec.ForProvider.Name,
+		Password:   password,
+	}
+
+	link, err := c.service.CreateSharedLink(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create shared link")
+	}
+
+	meta.SetExternalName(cr, link.Name)
+
+	return link, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*sharedlinkv1beta1.SharedLink)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSharedLink)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "sharedlink.update", "SharedLink", cr.GetName(), "update")
+	defer span.End()
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// Resolve the password first, so that a missing Secret does not leave
+	// the link deleted.
+	password, err := c.password(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// Shared links cannot be modified in place, so a link whose password
+	// changed is replaced under the same name. This issues a new URL, which
+	// is republished below.
+	err = c.service.DeleteSharedLink(ctx, siteDomain, linkName(cr))
+	if err != nil && !clients.IsNotFound(err) {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to delete shared link")
+	}
+
+	link, err := c.create(ctx, siteDomain, cr, password)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: connectionDetails(link, password),
+	}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*sharedlinkv1beta1.SharedLink)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotSharedLink)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "sharedlink.delete", "SharedLink", cr.GetName(), "delete")
+	defer span.End()
+
+	cr.SetConditions(xpv1.Deleting())
+
+	siteDomain, err := getSiteDomain(cr)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+
+	err = c.service.DeleteSharedLink(ctx, siteDomain, linkName(cr))
+	if err != nil && !clients.IsNotFound(err) {
+		return managed.ExternalDelete{}, errors.Wrap(err, "failed to delete shared link")
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	// Nothing to disconnect for Plausible API client
+	return nil
+}
@@ -0,0 +1,568 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharedlink
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	sharedlinkv1beta1 "github.com/rossigee/provider-plausible/apis/sharedlink/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/clients/fake"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newExternal(t *testing.T, f *fake.Plausible) *external {
+	t.Helper()
+	return &external{service: f.NewClient(t)}
+}
+
+func sharedLink(name string, password *string, externalName string) *sharedlinkv1beta1.SharedLink {
+	cr := &sharedlinkv1beta1.SharedLink{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: sharedlinkv1beta1.SharedLinkSpec{
+			ForProvider: sharedlinkv1beta1.SharedLinkParameters{
+				SiteDomain: stringPtr("example.com"),
+				Name:       name,
+				Password:   password,
+			},
+		},
+	}
+	if externalName != "" {
+		meta.SetExternalName(cr, externalName)
+	}
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	link := clients.SharedLink{Name: "client", URL: "https://plausible.io/share/x", HasPassword: true}
+	deleting := sharedLink("new", nil, "client")
+	deleting.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	cases := map[string]struct {
+		links            map[string]clients.SharedLink
+		cr               *sharedlinkv1beta1.SharedLink
+		want             managed.ExternalObservation
+		wantExternalName string
+	}{
+		"NotFound": {
+			cr:   sharedLink("client", nil, ""),
+			want: managed.ExternalObservation{ResourceExists: false},
+		},
+		"UpToDate": {
+			links: map[string]clients.SharedLink{"client": link},
+			cr:    sharedLink("client", stringPtr("secret"), "client"),
+			want: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL:      []byte(link.URL),
+					keyPassword: []byte("secret"),
+				},
+			},
+			wantExternalName: "client",
+		},
+		"AdoptedBySpecName": {
+			// The SharedLink is named dashboard, but manages the link named
+			// client. A link named dashboard must not be adopted.
+			links: map[string]clients.SharedLink{
+				"client":    link,
+				"dashboard": {Name: "dashboard", URL: "https://plausible.io/share/y"},
+			},
+			cr: sharedLink("client", stringPtr("secret"), ""),
+			want: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL:      []byte(link.URL),
+					keyPassword: []byte("secret"),
+				},
+			},
+			wantExternalName: "client",
+		},
+		"PasswordProtectionDrift": {
+			links: map[string]clients.SharedLink{"client": link},
+			cr:    sharedLink("client", nil, "client"),
+			want: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: false,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL: []byte(link.URL),
+				},
+			},
+			wantExternalName: "client",
+		},
+		"Renamed": {
+			links:            map[string]clients.SharedLink{"client": link},
+			cr:               sharedLink("new", nil, "client"),
+			want:             managed.ExternalObservation{ResourceExists: false},
+			wantExternalName: "client",
+		},
+		"RenamedWhileDeleting": {
+			links: map[string]clients.SharedLink{"client": link},
+			cr:    deleting,
+			want: managed.ExternalObservation{
+				ResourceExists: true,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL: []byte(link.URL),
+				},
+			},
+			wantExternalName: "client",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, &fake.Plausible{SharedLinks: tc.links})
+
+			got, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if en := meta.GetExternalName(tc.cr); en != tc.wantExternalName {
+				t.Errorf("Observe(...): external name = %q, want %q", en, tc.wantExternalName)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		links       map[string]clients.SharedLink
+		cr          *sharedlinkv1beta1.SharedLink
+		wantLinks   []string
+		wantDeleted []string
+	}{
+		"Created": {
+			cr:        sharedLink("client", stringPtr("secret"), ""),
+			wantLinks: []string{"client"},
+		},
+		"Renamed": {
+			links: map[string]clients.SharedLink{
+				"old": {Name: "old", URL: "https://plausible.io/share/x"},
+			},
+			cr:          sharedLink("client", stringPtr("secret"), "old"),
+			wantLinks:   []string{"client"},
+			wantDeleted: []string{"old"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fake.Plausible{SharedLinks: tc.links}
+			e := newExternal(t, f)
+
+			got, err := e.Create(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("Create(...): unexpected error: %v", err)
+			}
+
+			want := managed.ExternalCreation{
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL:      []byte("https://plausible.io/share/example.com?auth=client"),
+					keyPassword: []byte("secret"),
+				},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+			if en := meta.GetExternalName(tc.cr); en != "client" {
+				t.Errorf("Create(...): external name = %q, want %q", en, "client")
+			}
+			if !f.SharedLinks["client"].HasPassword {
+				t.Errorf("Create(...): expected link to be password protected")
+			}
+			if diff := cmp.Diff(tc.wantLinks, slices.Sorted(maps.Keys(f.SharedLinks))); diff != "" {
+				t.Errorf("Create(...): links -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, f.Deleted); diff != "" {
+				t.Errorf("Create(...): deleted links -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	f := &fake.Plausible{SharedLinks: map[string]clients.SharedLink{
+		"client": {Name: "client", URL: "https://plausible.io/share/x"},
+	}}
+	e := newExternal(t, f)
+	cr := sharedLink("client", stringPtr("secret"), "client")
+
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"client"}, f.Deleted); diff != "" {
+		t.Errorf("Update(...): deleted links -want, +got:\n%s", diff)
+	}
+	if !f.SharedLinks["client"].HasPassword {
+		t.Errorf("Update(...): expected link to be replaced by a password protected one")
+	}
+}
+
+// TestRename renames a SharedLink and checks that it converges, although the
+// managed reconciler keeps no external name set by Update.
+func TestRename(t *testing.T) {
+	f := &fake.Plausible{}
+	e := newExternal(t, f)
+	cr := sharedLink("old", nil, "")
+	ctx := context.Background()
+
+	if err := fake.Reconcile(ctx, e, cr); err != nil {
+		t.Fatalf("Reconcile(...): unexpected error: %v", err)
+	}
+	cr.Spec.ForProvider.Name = "new"
+	for range 2 {
+		if err := fake.Reconcile(ctx, e, cr); err != nil {
+			t.Fatalf("Reconcile(...): unexpected error: %v", err)
+		}
+	}
+
+	if en := meta.GetExternalName(cr); en != "new" {
+		t.Errorf("external name = %q, want %q", en, "new")
+	}
+	if diff := cmp.Diff([]string{"new"}, slices.Sorted(maps.Keys(f.SharedLinks))); diff != "" {
+		t.Errorf("links -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"old"}, f.Deleted); diff != "" {
+		t.Errorf("deleted links -want, +got:\n%s", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		links map[string]clients.SharedLink
+	}{
+		"Successful": {
+			links: map[string]clients.SharedLink{"client": {Name: "client"}},
+		},
+		"AlreadyDeleted": {
+			links: map[string]clients.SharedLink{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fake.Plausible{SharedLinks: tc.links}
+			e := newExternal(t, f)
+
+			if _, err := e.Delete(context.Background(), sharedLink("client", nil, "client")); err != nil {
+				t.Errorf("Delete(...): unexpected error: %v", err)
+			}
+			if _, ok := f.SharedLinks["client"]; ok {
+				t.Errorf("Delete(...): expected link to be removed")
+			}
+		})
+	}
+}
+
+// secrets returns a client that serves the data of Secrets in the default
+// namespace by name.
+func secrets(data map[string]map[string][]byte) client.Client {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			d, ok := data[key.Name]
+			if !ok || key.Namespace != "default" {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+			}
+			obj.(*corev1.Secret).Data = d
+			return nil
+		},
+	}
+}
+
+func TestPasswordSecretRef(t *testing.T) {
+	link := clients.SharedLink{Name: "client", URL: "https://plausible.io/share/x", HasPassword: true}
+
+	cases := map[string]struct {
+		published map[string][]byte
+		secret    map[string][]byte
+		want      managed.ExternalObservation
+		wantErr   bool
+	}{
+		"Unchanged": {
+			published: map[string][]byte{keyPassword: []byte("old")},
+			secret:    map[string][]byte{"password": []byte("old")},
+			want: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL:      []byte(link.URL),
+					keyPassword: []byte("old"),
+				},
+			},
+		},
+		// The password the link was created with stays published until
+		// the link is replaced.
+		"Rotated": {
+			published: map[string][]byte{keyPassword: []byte("old")},
+			secret:    map[string][]byte{"password": []byte("new")},
+			want: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: false,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL:      []byte(link.URL),
+					keyPassword: []byte("old"),
+				},
+			},
+		},
+		"NothingPublished": {
+			published: map[string][]byte{},
+			secret:    map[string][]byte{"password": []byte("new")},
+			want: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL:      []byte(link.URL),
+					keyPassword: []byte("new"),
+				},
+			},
+		},
+		// Without a connection Secret there is nothing to compare with.
+		"NoConnectionSecret": {
+			secret: map[string][]byte{"password": []byte("new")},
+			want: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+				ConnectionDetails: managed.ConnectionDetails{
+					keyURL:      []byte(link.URL),
+					keyPassword: []byte("new"),
+				},
+			},
+		},
+		"MissingKey": {
+			published: map[string][]byte{keyPassword: []byte("old")},
+			secret:    map[string][]byte{"other": []byte("new")},
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, &fake.Plausible{SharedLinks: map[string]clients.SharedLink{"client": link}})
+			e.kube = secrets(map[string]map[string][]byte{"dashboard": tc.secret, "dashboard-conn": tc.published})
+
+			cr := sharedLink("client", nil, "client")
+			cr.SetNamespace("default")
+			cr.Spec.ForProvider.PasswordSecretRef = &xpv1.LocalSecretKeySelector{
+				LocalSecretReference: xpv1.LocalSecretReference{Name: "dashboard"},
+				Key:                  "password",
+			}
+			if tc.published != nil {
+				cr.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: "dashboard-conn"}
+			}
+
+			got, err := e.Observe(context.Background(), cr)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Observe(...): expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	generate := func(connectionSecret string) *sharedlinkv1beta1.SharedLink {
+		cr := sharedLink("client", nil, "")
+		cr.SetNamespace("default")
+		generated := true
+		cr.Spec.ForProvider.GeneratePassword = &generated
+		if connectionSecret != "" {
+			cr.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: connectionSecret}
+		}
+		return cr
+	}
+
+	t.Run("Generated", func(t *testing.T) {
+		f := &fake.Plausible{}
+		e := newExternal(t, f)
+		e.kube = secrets(nil)
+		cr := generate("dashboard-conn")
+
+		got, err := e.Create(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("Create(...): unexpected error: %v", err)
+		}
+		password := string(got.ConnectionDetails[keyPassword])
+		if len(password) != 32 {
+			t.Errorf("Create(...): generated password %q, want 32 characters", password)
+		}
+		if !f.SharedLinks["client"].HasPassword {
+			t.Errorf("Create(...): expected link to be password protected")
+		}
+	})
+
+	t.Run("Published", func(t *testing.T) {
+		f := &fake.Plausible{}
+		e := newExternal(t, f)
+		e.kube = secrets(map[string]map[string][]byte{
+			"dashboard-conn": {keyPassword: []byte("published")},
+		})
+
+		got, err := e.Create(context.Background(), generate("dashboard-conn"))
+		if err != nil {
+			t.Fatalf("Create(...): unexpected error: %v", err)
+		}
+		if p := string(got.ConnectionDetails[keyPassword]); p != "published" {
+			t.Errorf("Create(...): password %q, want the published password", p)
+		}
+	})
+
+	// A generated password that was never published, e.g. for an adopted
+	// link, was never sent to Plausible either, so the link is replaced
+	// rather than published with a password it does not have.
+	t.Run("AdoptedWithoutPublishedPassword", func(t *testing.T) {
+		link := clients.SharedLink{Name: "client", URL: "https://plausible.io/share/x", HasPassword: true}
+		f := &fake.Plausible{SharedLinks: map[string]clients.SharedLink{"client": link}}
+		e := newExternal(t, f)
+		e.kube = secrets(map[string]map[string][]byte{"dashboard-conn": {}})
+		cr := generate("dashboard-conn")
+
+		o, err := e.Observe(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("Observe(...): unexpected error: %v", err)
+		}
+		want := managed.ExternalObservation{
+			ResourceExists:    true,
+			ConnectionDetails: managed.ConnectionDetails{keyURL: []byte(link.URL)},
+		}
+		if diff := cmp.Diff(want, o); diff != "" {
+			t.Errorf("Observe(...): -want, +got:\n%s", diff)
+		}
+
+		u, err := e.Update(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("Update(...): unexpected error: %v", err)
+		}
+		if p := u.ConnectionDetails[keyPassword]; len(p) != 32 {
+			t.Errorf("Update(...): published password %q, want a generated one", p)
+		}
+		if diff := cmp.Diff([]string{"client"}, f.Deleted); diff != "" {
+			t.Errorf("Update(...): deleted -want, +got:\n%s", diff)
+		}
+	})
+
+	t.Run("NoConnectionSecret", func(t *testing.T) {
+		e := newExternal(t, &fake.Plausible{})
+		e.kube = secrets(nil)
+
+		if _, err := e.Create(context.Background(), generate("")); err == nil || err.Error() != errNoConnectionSecret {
+			t.Errorf("Create(...): got error %v, want %q", err, errNoConnectionSecret)
+		}
+	})
+}
+
+// A SharedLink whose password Secret was deleted first, e.g. along with its
+// namespace, can still be deleted.
+func TestDeleteWithoutPasswordSecret(t *testing.T) {
+	f := &fake.Plausible{SharedLinks: map[string]clients.SharedLink{"client": {Name: "client", HasPassword: true}}}
+	e := newExternal(t, f)
+	e.kube = secrets(nil)
+
+	cr := sharedLink("client", nil, "client")
+	cr.SetNamespace("default")
+	cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	cr.Spec.ForProvider.PasswordSecretRef = &xpv1.LocalSecretKeySelector{
+		LocalSecretReference: xpv1.LocalSecretReference{Name: "dashboard"},
+		Key:                  "password",
+	}
+
+	if err := fake.Reconcile(context.Background(), e, cr); err != nil {
+		t.Fatalf("Reconcile(...): unexpected error: %v", err)
+	}
+	if _, err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("Delete(...): unexpected error: %v", err)
+	}
+	if _, ok := f.SharedLinks["client"]; ok {
+		t.Error("Delete(...): expected link to be removed")
+	}
+}
+
+func TestPasswordSecretUsers(t *testing.T) {
+	link := func(namespace, name, secret string) sharedlinkv1beta1.SharedLink {
+		cr := sharedLink("client", nil, "")
+		cr.SetNamespace(namespace)
+		cr.SetName(name)
+		if secret != "" {
+			cr.Spec.ForProvider.PasswordSecretRef = &xpv1.LocalSecretKeySelector{
+				LocalSecretReference: xpv1.LocalSecretReference{Name: secret},
+				Key:                  "password",
+			}
+		}
+		return *cr
+	}
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			for _, l := range []sharedlinkv1beta1.SharedLink{
+				link("default", "uses-secret", "dashboard"),
+				link("default", "uses-other-secret", "other"),
+				link("default", "unprotected", ""),
+				link("other", "other-namespace", "dashboard"),
+			} {
+				indexed := fields.Set{}
+				for _, name := range passwordSecretName(&l) {
+					indexed[passwordSecretIndex] = name
+				}
+				if l.GetNamespace() == lo.Namespace && lo.FieldSelector.Matches(indexed) {
+					obj.(*sharedlinkv1beta1.SharedLinkList).Items = append(obj.(*sharedlinkv1beta1.SharedLinkList).Items, l)
+				}
+			}
+			return nil
+		},
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dashboard"}}
+	got := passwordSecretUsers(kube)(context.Background(), secret)
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "uses-secret"}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("passwordSecretUsers(...): -want, +got:\n%s", diff)
+	}
+}
+
+// Helper function to create string pointers
+func stringPtr(s string) *string {
+	return &s
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -28,32 +29,41 @@ import (
 	"github.com/pkg/errors"
 	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
 	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/features"
 	"github.com/rossigee/provider-plausible/internal/tracing"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	errNotSite      = "managed resource is not a Site custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
+	errNotSite             = "managed resource is not a Site custom resource"
+	errTrackPCUsage        = "cannot track ProviderConfig usage"
+	errGetPC               = "cannot get ProviderConfig"
+	errGetCreds            = "cannot get credentials"
+	errDeletionProtected   = "refusing to delete site: deletion protection is enabled; set spec.forProvider.deletionProtection to false or annotate the Site with " + sitev1beta1.AnnotationAllowDeletion + "=true"
+	errRemoveAllowDeletion = "cannot remove the " + sitev1beta1.AnnotationAllowDeletion + " annotation"
 )
 
+// reasonDeletionProtected explains why a Site is stuck deleting.
+const reasonDeletionProtected xpv1.ConditionReason = "DeletionProtected"
+
 // Setup adds a controller that reconciles Site managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(sitev1beta1.SiteGroupKind.String())
+	recorder := clients.NewEventRecorder(mgr.GetEventRecorderFor(name))
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(sitev1beta1.SiteGroupVersionKind),
 		managed.WithExternalConnector(&connector{
-			kube:         mgr.GetClient(),
-			usage:        clients.NewProviderConfigUsageTracker(mgr.GetClient()),
-			newServiceFn: clients.NewClient,
+			kube:               mgr.GetClient(),
+			usage:              clients.NewProviderConfigUsageTracker(mgr.GetClient()),
+			newServiceFn:       clients.NewClient,
+			recorder:           recorder,
+			deletionProtection: o.Features.Enabled(features.EnableDeletionProtection),
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(nil),
+		managed.WithRecorder(recorder),
 		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())))
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -70,6 +80,10 @@ type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
 	newServiceFn func(config clients.Config) *clients.Client
+	recorder     event.Recorder
+
+	// deletionProtection applies to Sites that do not set it themselves.
+	deletionProtection bool
 }
 
 // Connect typically produces an ExternalClient by:
@@ -90,14 +104,15 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 
 	svc := c.newServiceFn(*cfg)
 
-	return &external{service: svc, kube: c.kube}, nil
+	return clients.WithEvents(clients.WithErrorConditions(&external{service: svc, kube: c.kube, deletionProtection: c.deletionProtection}), c.recorder), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	service *clients.Client
-	kube    client.Client
+	service            *clients.Client
+	kube               client.Client
+	deletionProtection bool
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -105,89 +120,240 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotSite)
 	}
-	_, span := tracing.StartSpanWithAttrs(ctx, "site.observe", "Site", cr.GetName(), "observe")
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "site.observe", "Site", cr.GetName(), "observe")
 	defer span.End()
 
 	// If we have an external name (site ID), try to get by ID
-	if meta.GetExternalName(cr) != "" {
-		site, err := c.service.GetSite(meta.GetExternalName(cr))
+	var site *clients.Site
+	if name := meta.GetExternalName(cr); name != "" {
+		var err error
+		site, err = c.service.GetSite(ctx, name)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, "failed to get site by ID")
 		}
+	}
 
-		if site == nil {
-			return managed.ExternalObservation{
-				ResourceExists: false,
-			}, nil
+	// Otherwise look the site up by its desired domain, then by the domain
+	// it was last observed with in case a rename is still pending. This also
+	// adopts existing sites whose external name defaulted to the object name.
+	for _, domain := range []string{desiredDomain(cr.Spec.ForProvider), cr.Status.AtProvider.Domain} {
+		if site != nil || domain == "" {
+			continue
 		}
-
-		cr.Status.AtProvider = sitev1beta1.SiteObservation{
-			ID:     site.ID,
-			Domain: site.Domain,
-			TeamID: site.TeamID,
+		var err error
+		site, err = c.service.GetSiteByDomain(ctx, domain)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "failed to get site by domain")
 		}
+	}
 
-		cr.SetConditions(xpv1.Available())
-		cr.SetConditions(xpv1.ReconcileSuccess())
-
+	if site == nil {
 		return managed.ExternalObservation{
-			ResourceExists:   true,
-			ResourceUpToDate: c.isUpToDate(cr, site),
+			ResourceExists: false,
 		}, nil
 	}
 
-	// If no external name, try to find by domain
-	site, err := c.service.GetSiteByDomain(cr.Spec.ForProvider.Domain)
-	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "failed to get site by domain")
+	return c.observed(cr, site), nil
+}
+
+// desiredDomain returns the domain a site should have. The deprecated
+// newDomain parameter takes precedence over domain.
+func desiredDomain(p sitev1beta1.SiteParameters) string {
+	if p.NewDomain != nil && *p.NewDomain != "" {
+		return *p.NewDomain
 	}
+	return p.Domain
+}
 
-	if site == nil {
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
+// externalName returns the external name of a site: its ID, or its domain if
+// Plausible did not report an ID.
+func externalName(site *clients.Site) string {
+	if site.ID != "" {
+		return site.ID
 	}
+	return site.Domain
+}
 
-	// Set the external name to the site ID
-	meta.SetExternalName(cr, site.ID)
+// observed records an existing site in the status of cr, late-initializes
+// any parameters Plausible defaulted, and reports whether cr is up to date.
+// The site's connection details are published on every observation, so they
+// follow changes to its domain or to the ProviderConfig's base URL.
+func (c *external) observed(cr *sitev1beta1.Site, site *clients.Site) managed.ExternalObservation {
+	// Remember the domain the site had before it was last renamed.
+	previous := cr.Status.AtProvider.PreviousDomain
+	if last := cr.Status.AtProvider.Domain; last != "" && last != site.Domain {
+		previous = last
+	}
 
 	cr.Status.AtProvider = sitev1beta1.SiteObservation{
-		ID:     site.ID,
-		Domain: site.Domain,
-		TeamID: site.TeamID,
+		ID:                         site.ID,
+		Domain:                     site.Domain,
+		PreviousDomain:             previous,
+		TeamID:                     site.TeamID,
+		Timezone:                   site.Timezone,
+		TrackerScriptConfiguration: trackerObservation(site.TrackerScriptConfiguration),
 	}
 
 	cr.SetConditions(xpv1.Available())
 	cr.SetConditions(xpv1.ReconcileSuccess())
 
+	// A site found by domain, or renamed while its domain served as its ID,
+	// gets a new external name. Reporting it as late-initialized persists it.
+	li := false
+	if name := externalName(site); meta.GetExternalName(cr) != name {
+		meta.SetExternalName(cr, name)
+		li = true
+	}
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: c.isUpToDate(cr, site),
-	}, nil
+		ResourceExists:          true,
+		ResourceUpToDate:        isUpToDate(cr),
+		ResourceLateInitialized: lateInitialize(&cr.Spec.ForProvider, cr.Status.AtProvider) || li,
+		ConnectionDetails:       connectionDetails(site, c.service.Tracking(site)),
+	}
+}
+
+// connectionDetails returns what a web page needs to report to Plausible for
+// site.
+func connectionDetails(site *clients.Site, t clients.Tracking) managed.ConnectionDetails {
+	return managed.ConnectionDetails{
+		"siteId":          []byte(site.ID),
+		"domain":          []byte(site.Domain),
+		"scriptURL":       []byte(t.ScriptURL),
+		"eventEndpoint":   []byte(t.EventEndpoint),
+		"trackingSnippet": []byte(t.Snippet),
+	}
 }
 
-func (c *external) isUpToDate(cr *sitev1beta1.Site, site *clients.Site) bool {
-	// Check if domain needs to be updated
-	if cr.Spec.ForProvider.NewDomain != nil && *cr.Spec.ForProvider.NewDomain != site.Domain {
+// isUpToDate compares the parameters of cr with the site last observed in its
+// status. Parameters that are not set are not compared.
+func isUpToDate(cr *sitev1beta1.Site) bool {
+	p, o := cr.Spec.ForProvider, cr.Status.AtProvider
+
+	if desiredDomain(p) != o.Domain {
+		return false
+	}
+	if p.Timezone != nil && *p.Timezone != o.Timezone {
 		return false
 	}
 
-	// Note: Team ID and timezone cannot be updated after creation via API
+	// Note: Team ID cannot be updated after creation via API
+	return trackerUpToDate(p.TrackerScriptConfiguration, o.TrackerScriptConfiguration)
+}
+
+func trackerUpToDate(want, got *sitev1beta1.TrackerScriptConfiguration) bool {
+	if want == nil {
+		return true
+	}
+	if got == nil {
+		got = &sitev1beta1.TrackerScriptConfiguration{}
+	}
+	return upToDate(want.InstallationType, got.InstallationType) &&
+		upToDate(want.OutboundLinks, got.OutboundLinks) &&
+		upToDate(want.FileDownloads, got.FileDownloads) &&
+		upToDate(want.FormSubmissions, got.FormSubmissions) &&
+		upToDate(want.HashBasedRouting, got.HashBasedRouting) &&
+		upToDate(want.Track404Pages, got.Track404Pages) &&
+		upToDate(want.TaggedEvents, got.TaggedEvents) &&
+		upToDate(want.RevenueTracking, got.RevenueTracking) &&
+		upToDate(want.PageviewProps, got.PageviewProps)
+}
+
+// upToDate returns true if want is unset or equal to got.
+func upToDate[T comparable](want, got *T) bool {
+	return want == nil || (got != nil && *want == *got)
+}
+
+// lateInitialize fills in unset parameters from the observed site, so that
+// defaults chosen by Plausible are recorded in the spec. It returns true if
+// any parameter was set.
+func lateInitialize(p *sitev1beta1.SiteParameters, o sitev1beta1.SiteObservation) bool {
+	li := false
+	if o.Timezone != "" {
+		li = lateInit(&p.Timezone, &o.Timezone) || li
+	}
+
+	if o.TrackerScriptConfiguration == nil {
+		return li
+	}
+	t := p.TrackerScriptConfiguration
+	if t == nil {
+		t = &sitev1beta1.TrackerScriptConfiguration{}
+	}
+	ot := o.TrackerScriptConfiguration
+	tli := lateInit(&t.InstallationType, ot.InstallationType)
+	tli = lateInit(&t.OutboundLinks, ot.OutboundLinks) || tli
+	tli = lateInit(&t.FileDownloads, ot.FileDownloads) || tli
+	tli = lateInit(&t.FormSubmissions, ot.FormSubmissions) || tli
+	tli = lateInit(&t.HashBasedRouting, ot.HashBasedRouting) || tli
+	tli = lateInit(&t.Track404Pages, ot.Track404Pages) || tli
+	tli = lateInit(&t.TaggedEvents, ot.TaggedEvents) || tli
+	tli = lateInit(&t.RevenueTracking, ot.RevenueTracking) || tli
+	tli = lateInit(&t.PageviewProps, ot.PageviewProps) || tli
+	if tli {
+		p.TrackerScriptConfiguration = t
+	}
+
+	return li || tli
+}
+
+// lateInit sets *dst to a copy of *src if dst is unset and src is set.
+func lateInit[T any](dst **T, src *T) bool {
+	if *dst != nil || src == nil {
+		return false
+	}
+	v := *src
+	*dst = &v
 	return true
 }
 
+func trackerObservation(t *clients.TrackerScriptConfiguration) *sitev1beta1.TrackerScriptConfiguration {
+	if t == nil {
+		return nil
+	}
+	return &sitev1beta1.TrackerScriptConfiguration{
+		InstallationType: t.InstallationType,
+		OutboundLinks:    t.OutboundLinks,
+		FileDownloads:    t.FileDownloads,
+		FormSubmissions:  t.FormSubmissions,
+		HashBasedRouting: t.HashBasedRouting,
+		Track404Pages:    t.Track404Pages,
+		TaggedEvents:     t.TaggedEvents,
+		RevenueTracking:  t.RevenueTracking,
+		PageviewProps:    t.PageviewProps,
+	}
+}
+
+func trackerParameters(t *sitev1beta1.TrackerScriptConfiguration) *clients.TrackerScriptConfiguration {
+	if t == nil {
+		return nil
+	}
+	return &clients.TrackerScriptConfiguration{
+		InstallationType: t.InstallationType,
+		OutboundLinks:    t.OutboundLinks,
+		FileDownloads:    t.FileDownloads,
+		FormSubmissions:  t.FormSubmissions,
+		HashBasedRouting: t.HashBasedRouting,
+		Track404Pages:    t.Track404Pages,
+		TaggedEvents:     t.TaggedEvents,
+		RevenueTracking:  t.RevenueTracking,
+		PageviewProps:    t.PageviewProps,
+	}
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*sitev1beta1.Site)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotSite)
 	}
-	_, span := tracing.StartSpanWithAttrs(ctx, "site.create", "Site", cr.GetName(), "create")
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "site.create", "Site", cr.GetName(), "create")
 	defer span.End()
 
 	cr.SetConditions(xpv1.Creating())
 
 	req := clients.CreateSiteRequest{
-		Domain: cr.Spec.ForProvider.Domain,
+		Domain:                     desiredDomain(cr.Spec.ForProvider),
+		TrackerScriptConfiguration: trackerParameters(cr.Spec.ForProvider.TrackerScriptConfiguration),
 	}
 
 	if cr.Spec.ForProvider.TeamID != nil {
@@ -198,36 +364,53 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		req.Timezone = *cr.Spec.ForProvider.Timezone
 	}
 
-	site, err := c.service.CreateSite(req)
+	site, err := c.service.CreateSite(ctx, req)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to create site")
 	}
 
-	meta.SetExternalName(cr, site.ID)
+	meta.SetExternalName(cr, externalName(site))
 
 	// Return connection details for the created site
 	return managed.ExternalCreation{
-		ConnectionDetails: managed.ConnectionDetails{
-			"siteId": []byte(site.ID),
-			"domain": []byte(site.Domain),
-		},
+		ConnectionDetails: connectionDetails(site, c.service.Tracking(site)),
 	}, nil
 }
 
+// updateRequest returns the changes needed to bring the site last observed
+// in the status of cr up to date with its parameters.
+func updateRequest(cr *sitev1beta1.Site) clients.UpdateSiteRequest {
+	p, o := cr.Spec.ForProvider, cr.Status.AtProvider
+
+	var req clients.UpdateSiteRequest
+	if d := desiredDomain(p); d != o.Domain {
+		req.Domain = d
+	}
+	if p.Timezone != nil && *p.Timezone != o.Timezone {
+		req.Timezone = *p.Timezone
+	}
+	if !trackerUpToDate(p.TrackerScriptConfiguration, o.TrackerScriptConfiguration) {
+		req.TrackerScriptConfiguration = trackerParameters(p.TrackerScriptConfiguration)
+	}
+
+	return req
+}
+
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
 	cr, ok := mg.(*sitev1beta1.Site)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotSite)
 	}
-	_, span := tracing.StartSpanWithAttrs(ctx, "site.update", "Site", cr.GetName(), "update")
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "site.update", "Site", cr.GetName(), "update")
 	defer span.End()
 
-	// Only domain can be updated
-	if cr.Spec.ForProvider.NewDomain != nil && *cr.Spec.ForProvider.NewDomain != cr.Status.AtProvider.Domain {
-		_, err := c.service.UpdateSite(meta.GetExternalName(cr), *cr.Spec.ForProvider.NewDomain)
-		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, "failed to update site domain")
-		}
+	req := updateRequest(cr)
+	if req == (clients.UpdateSiteRequest{}) {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	if _, err := c.service.UpdateSite(ctx, meta.GetExternalName(cr), req); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to update site")
 	}
 
 	return managed.ExternalUpdate{}, nil
@@ -238,19 +421,48 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalDelete{}, errors.New(errNotSite)
 	}
-	_, span := tracing.StartSpanWithAttrs(ctx, "site.delete", "Site", cr.GetName(), "delete")
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "site.delete", "Site", cr.GetName(), "delete")
 	defer span.End()
 
+	if c.deletionProtected(cr) {
+		cond := xpv1.Unavailable().WithMessage(errDeletionProtected)
+		cond.Reason = reasonDeletionProtected
+		cr.SetConditions(cond)
+		return managed.ExternalDelete{}, errors.New(errDeletionProtected)
+	}
+
 	cr.SetConditions(xpv1.Deleting())
 
-	err := c.service.DeleteSite(meta.GetExternalName(cr))
+	err := c.service.DeleteSite(ctx, meta.GetExternalName(cr))
 	if err != nil && !clients.IsNotFound(err) {
 		return managed.ExternalDelete{}, errors.Wrap(err, "failed to delete site")
 	}
 
+	// The allow-deletion annotation overrides deletion protection only once,
+	// so remove it as soon as it has been used.
+	if _, ok := cr.GetAnnotations()[sitev1beta1.AnnotationAllowDeletion]; ok {
+		patch := client.MergeFrom(cr.DeepCopy())
+		meta.RemoveAnnotations(cr, sitev1beta1.AnnotationAllowDeletion)
+		if err := c.kube.Patch(ctx, cr, patch); err != nil {
+			return managed.ExternalDelete{}, errors.Wrap(err, errRemoveAllowDeletion)
+		}
+	}
+
 	return managed.ExternalDelete{}, nil
 }
 
+// deletionProtected returns true if the site of cr must not be deleted in
+// Plausible.
+func (c *external) deletionProtected(cr *sitev1beta1.Site) bool {
+	if cr.GetAnnotations()[sitev1beta1.AnnotationAllowDeletion] == "true" {
+		return false
+	}
+	if p := cr.Spec.ForProvider.DeletionProtection; p != nil {
+		return *p
+	}
+	return c.deletionProtection
+}
+
 func (c *external) Disconnect(ctx context.Context) error {
 	// Nothing to disconnect for Plausible API client
 	return nil
@@ -19,7 +19,10 @@ package site
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -31,6 +34,7 @@ import (
 	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
 	"github.com/rossigee/provider-plausible/internal/clients"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // PlausibleService defines the interface for Plausible operations
@@ -693,7 +697,7 @@ func TestDelete(t *testing.T) {
 			args: args{
 				service: &MockPlausibleClient{
 					MockDeleteSite: func(siteID string) error {
-						return fmt.Errorf("API request failed with status 404: Not Found")
+						return &clients.APIError{StatusCode: 404, Message: "Not Found"}
 					},
 				},
 				cr: &sitev1beta1.Site{
@@ -725,6 +729,305 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestIsUpToDate(t *testing.T) {
+	observed := sitev1beta1.SiteObservation{
+		Domain:   "example.com",
+		Timezone: "UTC",
+		TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+			OutboundLinks: ptr(false),
+			FileDownloads: ptr(true),
+		},
+	}
+
+	cases := map[string]struct {
+		params sitev1beta1.SiteParameters
+		want   bool
+	}{
+		"NothingSet": {
+			params: sitev1beta1.SiteParameters{Domain: "example.com"},
+			want:   true,
+		},
+		"Matching": {
+			params: sitev1beta1.SiteParameters{
+				Domain:   "example.com",
+				Timezone: ptr("UTC"),
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					FileDownloads: ptr(true),
+				},
+			},
+			want: true,
+		},
+		"TimezoneDrift": {
+			params: sitev1beta1.SiteParameters{Domain: "example.com", Timezone: ptr("Europe/London")},
+			want:   false,
+		},
+		"TrackerDrift": {
+			params: sitev1beta1.SiteParameters{
+				Domain: "example.com",
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					OutboundLinks: ptr(true),
+				},
+			},
+			want: false,
+		},
+		"TrackerSettingNotObserved": {
+			params: sitev1beta1.SiteParameters{
+				Domain: "example.com",
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					HashBasedRouting: ptr(false),
+				},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &sitev1beta1.Site{
+				Spec:   sitev1beta1.SiteSpec{ForProvider: tc.params},
+				Status: sitev1beta1.SiteStatus{AtProvider: observed},
+			}
+			if got := isUpToDate(cr); got != tc.want {
+				t.Errorf("isUpToDate(...) = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	observed := sitev1beta1.SiteObservation{
+		Timezone: "UTC",
+		TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+			InstallationType: ptr("manual"),
+			OutboundLinks:    ptr(false),
+		},
+	}
+
+	cases := map[string]struct {
+		params sitev1beta1.SiteParameters
+		want   sitev1beta1.SiteParameters
+		li     bool
+	}{
+		"FillsDefaults": {
+			params: sitev1beta1.SiteParameters{Domain: "example.com"},
+			want: sitev1beta1.SiteParameters{
+				Domain:   "example.com",
+				Timezone: ptr("UTC"),
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					InstallationType: ptr("manual"),
+					OutboundLinks:    ptr(false),
+				},
+			},
+			li: true,
+		},
+		"KeepsDesiredValues": {
+			params: sitev1beta1.SiteParameters{
+				Domain:   "example.com",
+				Timezone: ptr("Europe/London"),
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					InstallationType: ptr("gtm"),
+					OutboundLinks:    ptr(true),
+				},
+			},
+			want: sitev1beta1.SiteParameters{
+				Domain:   "example.com",
+				Timezone: ptr("Europe/London"),
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					InstallationType: ptr("gtm"),
+					OutboundLinks:    ptr(true),
+				},
+			},
+			li: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			li := lateInitialize(&tc.params, observed)
+			if li != tc.li {
+				t.Errorf("lateInitialize(...) = %t, want %t", li, tc.li)
+			}
+			if diff := cmp.Diff(tc.want, tc.params); diff != "" {
+				t.Errorf("lateInitialize(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateRequest(t *testing.T) {
+	cr := &sitev1beta1.Site{
+		Spec: sitev1beta1.SiteSpec{
+			ForProvider: sitev1beta1.SiteParameters{
+				Domain:   "example.com",
+				Timezone: ptr("Europe/London"),
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					OutboundLinks: ptr(true),
+				},
+			},
+		},
+		Status: sitev1beta1.SiteStatus{
+			AtProvider: sitev1beta1.SiteObservation{
+				Domain:   "example.com",
+				Timezone: "UTC",
+				TrackerScriptConfiguration: &sitev1beta1.TrackerScriptConfiguration{
+					OutboundLinks: ptr(false),
+				},
+			},
+		},
+	}
+
+	want := clients.UpdateSiteRequest{
+		Timezone: "Europe/London",
+		TrackerScriptConfiguration: &clients.TrackerScriptConfiguration{
+			OutboundLinks: ptr(true),
+		},
+	}
+	if diff := cmp.Diff(want, updateRequest(cr)); diff != "" {
+		t.Errorf("updateRequest(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDeletionProtection(t *testing.T) {
+	cases := map[string]struct {
+		providerDefault bool
+		protection      *bool
+		annotations     map[string]string
+		wantDelete      bool
+	}{
+		"ProviderDefault": {
+			providerDefault: true,
+		},
+		"ProviderDefaultOff": {
+			wantDelete: true,
+		},
+		"DisabledOnSite": {
+			providerDefault: true,
+			protection:      ptr(false),
+			wantDelete:      true,
+		},
+		"EnabledOnSite": {
+			protection: ptr(true),
+		},
+		"AllowDeletionAnnotation": {
+			providerDefault: true,
+			protection:      ptr(true),
+			annotations:     map[string]string{sitev1beta1.AnnotationAllowDeletion: "true"},
+			wantDelete:      true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deleted = r.Method == http.MethodDelete
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			e := &external{
+				service:            clients.NewClient(clients.Config{BaseURL: srv.URL, APIKey: "test-key"}),
+				kube:               &test.MockClient{MockPatch: test.NewMockPatchFn(nil)},
+				deletionProtection: tc.providerDefault,
+			}
+			cr := &sitev1beta1.Site{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"crossplane.io/external-name": "example.com"},
+				},
+				Spec: sitev1beta1.SiteSpec{
+					ForProvider: sitev1beta1.SiteParameters{
+						Domain:             "example.com",
+						DeletionProtection: tc.protection,
+					},
+				},
+			}
+			for k, v := range tc.annotations {
+				cr.Annotations[k] = v
+			}
+
+			_, err := e.Delete(context.Background(), cr)
+			if tc.wantDelete {
+				if err != nil {
+					t.Errorf("Delete(...): unexpected error: %v", err)
+				}
+			} else {
+				if err == nil {
+					t.Error("Delete(...): expected an error for a protected site")
+				}
+				if got := cr.GetCondition(xpv1.TypeReady).Reason; got != reasonDeletionProtected {
+					t.Errorf("Delete(...): Ready reason = %q, want %q", got, reasonDeletionProtected)
+				}
+			}
+			if deleted != tc.wantDelete {
+				t.Errorf("Delete(...): deleted = %t, want %t", deleted, tc.wantDelete)
+			}
+		})
+	}
+}
+
+func TestAllowDeletionConsumed(t *testing.T) {
+	cases := map[string]struct {
+		status      int
+		wantErr     bool
+		wantPatched bool
+	}{
+		"SiteDeleted": {
+			status:      http.StatusNoContent,
+			wantPatched: true,
+		},
+		"SiteAlreadyGone": {
+			status:      http.StatusNotFound,
+			wantPatched: true,
+		},
+		"DeleteFailed": {
+			status:  http.StatusBadRequest,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			patched := false
+			e := &external{
+				service:            clients.NewClient(clients.Config{BaseURL: srv.URL, APIKey: "test-key"}),
+				deletionProtection: true,
+				kube: &test.MockClient{
+					MockPatch: func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						patched = true
+						return nil
+					},
+				},
+			}
+			cr := &sitev1beta1.Site{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"crossplane.io/external-name":       "example.com",
+						sitev1beta1.AnnotationAllowDeletion: "true",
+					},
+				},
+				Spec: sitev1beta1.SiteSpec{ForProvider: sitev1beta1.SiteParameters{Domain: "example.com"}},
+			}
+			cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+			_, err := e.Delete(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Delete(...): error = %v, want error %t", err, tc.wantErr)
+			}
+			if patched != tc.wantPatched {
+				t.Errorf("Delete(...): patched = %t, want %t", patched, tc.wantPatched)
+			}
+			if _, kept := cr.GetAnnotations()[sitev1beta1.AnnotationAllowDeletion]; kept == tc.wantPatched {
+				t.Errorf("Delete(...): annotation kept = %t, want %t", kept, !tc.wantPatched)
+			}
+		})
+	}
+}
+
 // Helper function
 func ptr[T any](v T) *T {
 	return &v
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package team
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	teamv1beta1 "github.com/rossigee/provider-plausible/apis/team/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+	"github.com/rossigee/provider-plausible/internal/tracing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotTeam      = "managed resource is not a Team custom resource"
+	errNoTeamLookup = "either teamID or name must be specified"
+	errCannotCreate = "teams cannot be created through the Plausible API; reference an existing team by teamID or name"
+
+	msgAPIDisabled = "Sites API is not enabled for this team"
+)
+
+// keyTeamID is the connection detail key under which the team ID is published.
+const keyTeamID = "teamId"
+
+// Setup adds a controller that reconciles Team managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(teamv1beta1.TeamGroupKind.String())
+	recorder := clients.NewEventRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(teamv1beta1.TeamGroupVersionKind),
+		managed.WithExternalConnector(&connector{
+			kube:         mgr.GetClient(),
+			usage:        clients.NewProviderConfigUsageTracker(mgr.GetClient()),
+			newServiceFn: clients.NewClient,
+			recorder:     recorder,
+		}),
+		// The external name is the Plausible team ID, so it must not default
+		// to the name of the Kubernetes object.
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&teamv1beta1.Team{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(config clients.Config) *clients.Client
+	recorder     event.Recorder
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*teamv1beta1.Team)
+	if !ok {
+		return nil, errors.New(errNotTeam)
+	}
+
+	cfg, err := clients.GetConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := c.newServiceFn(*cfg)
+
+	return clients.WithEvents(clients.WithErrorConditions(&external{service: svc}), c.recorder), nil
+}
+
+// An ExternalClient observes an existing Plausible team. Teams cannot be
+// created, changed or deleted through the API, so only Observe has any effect.
+type external struct {
+	service *clients.Client
+}
+
+// findTeam picks the team identified by the managed resource. An explicit
+// teamID or a previously recorded external name wins over a name match.
+func findTeam(cr *teamv1beta1.Team, teams []clients.Team) *clients.Team {
+	ids := []string{meta.GetExternalName(cr)}
+	if cr.Spec.ForProvider.TeamID != nil {
+		ids = append([]string{*cr.Spec.ForProvider.TeamID}, ids...)
+	}
+
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		for i := range teams {
+			if teams[i].ID == id {
+				return &teams[i]
+			}
+		}
+	}
+
+	if cr.Spec.ForProvider.Name != nil {
+		for i := range teams {
+			if teams[i].Name == *cr.Spec.ForProvider.Name {
+				return &teams[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*teamv1beta1.Team)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotTeam)
+	}
+	ctx, span := tracing.StartSpanWithAttrs(ctx, "team.observe", "Team", cr.GetName(), "observe")
+	defer span.End()
+
+	if cr.Spec.ForProvider.TeamID == nil && cr.Spec.ForProvider.Name == nil && meta.GetExternalName(cr) == "" {
+		return managed.ExternalObservation{}, errors.New(errNoTeamLookup)
+	}
+
+	teams, err := c.service.ListTeams(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to list teams")
+	}
+
+	team := findTeam(cr, teams)
+	if team == nil {
+		return managed.ExternalObservation{
+			ResourceExists: false,
+		}, nil
+	}
+
+	meta.SetExternalName(cr, team.ID)
+
+	cr.Status.AtProvider = teamv1beta1.TeamObservation{
+		ID:         team.ID,
+		Name:       team.Name,
+		APIEnabled: team.APIEnabled,
+	}
+
+	if team.APIEnabled {
+		cr.SetConditions(xpv1.Available())
+	} else {
+		cr.SetConditions(xpv1.Unavailable().WithMessage(msgAPIDisabled))
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+		ConnectionDetails: managed.ConnectionDetails{
+			keyTeamID: []byte(team.ID),
+		},
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if _, ok := mg.(*teamv1beta1.Team); !ok {
+		return managed.ExternalCreation{}, errors.New(errNotTeam)
+	}
+	return managed.ExternalCreation{}, errors.New(errCannotCreate)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// Teams are read-only and always reported as up to date
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	// Deleting a Team only stops tracking it; the team itself is left alone
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	// Nothing to disconnect for Plausible API client
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	teamv1beta1 "github.com/rossigee/provider-plausible/apis/team/v1beta1"
+	"github.com/rossigee/provider-plausible/internal/clients"
+)
+
+func newExternal(t *testing.T, teams []clients.Team) *external {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sites/teams" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"teams": teams, "meta": map[string]interface{}{}})
+	}))
+	t.Cleanup(srv.Close)
+	return &external{service: clients.NewClient(clients.Config{BaseURL: srv.URL, APIKey: "test-key"})}
+}
+
+func TestObserve(t *testing.T) {
+	teams := []clients.Team{
+		{ID: "team-1", Name: "Marketing", APIEnabled: true},
+		{ID: "team-2", Name: "Engineering", APIEnabled: false},
+	}
+
+	cases := map[string]struct {
+		params       teamv1beta1.TeamParameters
+		externalName string
+		want         managed.ExternalObservation
+		wantErr      bool
+		obs          teamv1beta1.TeamObservation
+		cond         xpv1.Condition
+	}{
+		"ByTeamID": {
+			params: teamv1beta1.TeamParameters{TeamID: stringPtr("team-1")},
+			want: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{keyTeamID: []byte("team-1")},
+			},
+			obs:  teamv1beta1.TeamObservation{ID: "team-1", Name: "Marketing", APIEnabled: true},
+			cond: xpv1.Available(),
+		},
+		"ByName": {
+			params: teamv1beta1.TeamParameters{Name: stringPtr("Engineering")},
+			want: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{keyTeamID: []byte("team-2")},
+			},
+			obs:  teamv1beta1.TeamObservation{ID: "team-2", Name: "Engineering"},
+			cond: xpv1.Unavailable().WithMessage(msgAPIDisabled),
+		},
+		"ByExternalName": {
+			externalName: "team-1",
+			want: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{keyTeamID: []byte("team-1")},
+			},
+			obs:  teamv1beta1.TeamObservation{ID: "team-1", Name: "Marketing", APIEnabled: true},
+			cond: xpv1.Available(),
+		},
+		"NotFound": {
+			params: teamv1beta1.TeamParameters{TeamID: stringPtr("team-9")},
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"NothingToLookUp": {
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, teams)
+			cr := &teamv1beta1.Team{Spec: teamv1beta1.TeamSpec{ForProvider: tc.params}}
+			if tc.externalName != "" {
+				meta.SetExternalName(cr, tc.externalName)
+			}
+
+			got, err := e.Observe(context.Background(), cr)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Observe(...): expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.obs, cr.Status.AtProvider); diff != "" {
+				t.Errorf("Observe(...): atProvider -want, +got:\n%s", diff)
+			}
+			if !got.ResourceExists {
+				return
+			}
+			if en := meta.GetExternalName(cr); en != tc.obs.ID {
+				t.Errorf("Observe(...): external name = %q, want %q", en, tc.obs.ID)
+			}
+			if got := cr.GetCondition(xpv1.TypeReady); !got.Equal(tc.cond) {
+				t.Errorf("Observe(...): Ready condition = %v, want %v", got, tc.cond)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	e := &external{}
+	if _, err := e.Create(context.Background(), &teamv1beta1.Team{}); err == nil {
+		t.Errorf("Create(...): expected error, got nil")
+	}
+}
+
+// Helper function to create string pointers
+func stringPtr(s string) *string {
+	return &s
+}
@@ -24,4 +24,14 @@ const (
 	// Management Policies. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/91edeae3fcac96c6c8a1759a723981eea4bb77e4/design/design-doc-observe-only-resources.md
 	EnableAlphaManagementPolicies feature.Flag = "EnableAlphaManagementPolicies"
+
+	// EnableDeletionProtection refuses to delete Sites in Plausible unless
+	// their deletionProtection parameter is false or they are annotated to
+	// allow deletion.
+	EnableDeletionProtection feature.Flag = "EnableDeletionProtection"
+
+	// EnableAlphaFunnels enables the alpha Funnel kind. Plausible does not
+	// document the funnel endpoints of its Sites API, so they may change
+	// without notice.
+	EnableAlphaFunnels feature.Flag = "EnableAlphaFunnels"
 )
@@ -2,11 +2,13 @@ package tracing
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"strconv"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/propagation"
@@ -114,3 +116,37 @@ func getEnv(key, def string) string {
 	}
 	return def
 }
+
+// StartClientSpan starts a client span for an outbound request to the
+// Plausible API. route is the path template, e.g. /sites/:site_id, so that
+// spans for different sites share a name.
+func StartClientSpan(ctx context.Context, method, route string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "plausible "+method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(method),
+			semconv.HTTPRouteKey.String(route),
+		),
+	)
+}
+
+// EndClientSpan records the outcome of an outbound request on span and ends
+// it. statusCode is ignored if the request failed before a response arrived.
+func EndClientSpan(span trace.Span, statusCode int, err error) {
+	defer span.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(statusCode))
+	if statusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+}
+
+// InjectHeaders writes the W3C trace context of ctx into h, so the request
+// can be correlated with the span that sent it.
+func InjectHeaders(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"strings"
+
+	custompropertyv1beta1 "github.com/rossigee/provider-plausible/apis/customproperty/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var customPropertyValidator = validator[*custompropertyv1beta1.CustomProperty]{
+	kind:        custompropertyv1beta1.CustomPropertyGroupKind,
+	forProvider: func(cr *custompropertyv1beta1.CustomProperty) any { return cr.Spec.ForProvider },
+	validate:    validateCustomProperty,
+}
+
+func validateCustomProperty(cr *custompropertyv1beta1.CustomProperty) (admission.Warnings, field.ErrorList) {
+	p := cr.Spec.ForProvider
+	errs := validateSiteDomain(p.SiteDomain)
+
+	switch {
+	case p.Key == "":
+		errs = append(errs, field.Required(forProviderPath.Child("key"), ""))
+	case strings.TrimSpace(p.Key) != p.Key:
+		errs = append(errs, field.Invalid(forProviderPath.Child("key"), p.Key, "must not start or end with whitespace"))
+	}
+	return nil, errs
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var goalValidator = validator[*goalv1beta1.Goal]{
+	kind:        goalv1beta1.GoalGroupKind,
+	forProvider: func(cr *goalv1beta1.Goal) any { return cr.Spec.ForProvider },
+	validate:    validateGoal,
+	immutable:   immutableGoal,
+}
+
+func validateGoal(cr *goalv1beta1.Goal) (admission.Warnings, field.ErrorList) {
+	p := cr.Spec.ForProvider
+	errs := validateSiteDomain(p.SiteDomain)
+
+	switch p.GoalType {
+	case "event":
+		if p.EventName == nil || *p.EventName == "" {
+			errs = append(errs, field.Required(forProviderPath.Child("eventName"), "event goals need an event name"))
+		}
+		if p.PagePath != nil {
+			errs = append(errs, field.Forbidden(forProviderPath.Child("pagePath"), "only page goals have a page path"))
+		}
+		if p.ScrollThreshold != nil {
+			errs = append(errs, field.Forbidden(forProviderPath.Child("scrollThreshold"), "only page goals have a scroll threshold"))
+		}
+	case "page":
+		if p.PagePath == nil || *p.PagePath == "" {
+			errs = append(errs, field.Required(forProviderPath.Child("pagePath"), "page goals need a page path"))
+		} else if (*p.PagePath)[0] != '/' {
+			errs = append(errs, field.Invalid(forProviderPath.Child("pagePath"), *p.PagePath, "must start with /"))
+		}
+		if p.EventName != nil {
+			errs = append(errs, field.Forbidden(forProviderPath.Child("eventName"), "only event goals have an event name"))
+		}
+		if p.Currency != nil {
+			errs = append(errs, field.Forbidden(forProviderPath.Child("currency"), "only event goals have a currency"))
+		}
+	}
+	return nil, errs
+}
+
+// immutableGoal rejects changes to a Goal that would have it replaced, if its
+// replacePolicy does not allow that. Plausible cannot change a goal in place.
+func immutableGoal(old, cr *goalv1beta1.Goal) field.ErrorList {
+	if cr.Spec.ForProvider.ReplacePolicy != goalv1beta1.ReplacePolicyNever {
+		return nil
+	}
+
+	o, p := old.Spec.ForProvider, cr.Spec.ForProvider
+	var errs field.ErrorList
+	if o.GoalType != p.GoalType {
+		errs = append(errs, field.Invalid(forProviderPath.Child("goalType"), p.GoalType, "field is immutable when replacePolicy is Never"))
+	}
+	for _, f := range []struct {
+		name     string
+		old, new *string
+	}{
+		{"eventName", o.EventName, p.EventName},
+		{"pagePath", o.PagePath, p.PagePath},
+		{"displayName", o.DisplayName, p.DisplayName},
+		{"currency", o.Currency, p.Currency},
+	} {
+		if !equality.Semantic.DeepEqual(f.old, f.new) {
+			errs = append(errs, field.Invalid(forProviderPath.Child(f.name), value(f.new), "field is immutable when replacePolicy is Never"))
+		}
+	}
+	if !equality.Semantic.DeepEqual(o.ScrollThreshold, p.ScrollThreshold) {
+		errs = append(errs, field.Invalid(forProviderPath.Child("scrollThreshold"), value(p.ScrollThreshold), "field is immutable when replacePolicy is Never"))
+	}
+	if !equality.Semantic.DeepEqual(o.CustomProps, p.CustomProps) {
+		errs = append(errs, field.Invalid(forProviderPath.Child("customProps"), p.CustomProps, "field is immutable when replacePolicy is Never"))
+	}
+	return errs
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/mail"
+
+	guestv1beta1 "github.com/rossigee/provider-plausible/apis/guest/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var guestValidator = validator[*guestv1beta1.Guest]{
+	kind:        guestv1beta1.GuestGroupKind,
+	forProvider: func(cr *guestv1beta1.Guest) any { return cr.Spec.ForProvider },
+	validate:    validateGuest,
+}
+
+func validateGuest(cr *guestv1beta1.Guest) (admission.Warnings, field.ErrorList) {
+	p := cr.Spec.ForProvider
+	errs := validateSiteDomain(p.SiteDomain)
+
+	// Plausible invites a bare address, without a display name.
+	if a, err := mail.ParseAddress(p.Email); err != nil || a.Address != p.Email {
+		errs = append(errs, field.Invalid(forProviderPath.Child("email"), p.Email, "must be an email address such as jane@example.com"))
+	}
+	return nil, errs
+}
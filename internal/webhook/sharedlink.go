@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	sharedlinkv1beta1 "github.com/rossigee/provider-plausible/apis/sharedlink/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var sharedLinkValidator = validator[*sharedlinkv1beta1.SharedLink]{
+	kind:        sharedlinkv1beta1.SharedLinkGroupKind,
+	forProvider: func(cr *sharedlinkv1beta1.SharedLink) any { return cr.Spec.ForProvider },
+	validate:    validateSharedLink,
+}
+
+func validateSharedLink(cr *sharedlinkv1beta1.SharedLink) (admission.Warnings, field.ErrorList) {
+	p := cr.Spec.ForProvider
+	errs := validateSiteDomain(p.SiteDomain)
+	if p.Name == "" {
+		errs = append(errs, field.Required(forProviderPath.Child("name"), ""))
+	}
+
+	var w admission.Warnings
+	generate := p.GeneratePassword != nil && *p.GeneratePassword
+	switch {
+	case p.PasswordSecretRef != nil && generate:
+		w = append(w, "spec.forProvider.generatePassword is ignored because passwordSecretRef is set")
+	case generate && cr.GetWriteConnectionSecretToReference() == nil:
+		errs = append(errs, field.Required(field.NewPath("spec", "writeConnectionSecretToRef"), "a generated password is published to the connection secret"))
+	}
+	if p.Password != nil {
+		if p.PasswordSecretRef != nil || generate {
+			w = append(w, "spec.forProvider.password is ignored because passwordSecretRef or generatePassword is set")
+		} else {
+			w = append(w, "spec.forProvider.password is deprecated; use passwordSecretRef or generatePassword so the password is not stored in the spec")
+		}
+	}
+	return w, errs
+}
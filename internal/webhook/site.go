@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"time"
+	// Embed the IANA time zone database, which the provider image lacks.
+	_ "time/tzdata"
+
+	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var siteValidator = validator[*sitev1beta1.Site]{
+	kind:        sitev1beta1.SiteGroupKind,
+	forProvider: func(cr *sitev1beta1.Site) any { return cr.Spec.ForProvider },
+	validate:    validateSite,
+	immutable: func(old, cr *sitev1beta1.Site) field.ErrorList {
+		// Plausible cannot move a site to another team.
+		return unchanged(forProviderPath.Child("teamID"), old.Spec.ForProvider.TeamID, cr.Spec.ForProvider.TeamID)
+	},
+}
+
+func validateSite(cr *sitev1beta1.Site) (admission.Warnings, field.ErrorList) {
+	p := cr.Spec.ForProvider
+	errs := validateDomain(forProviderPath.Child("domain"), p.Domain)
+
+	var w admission.Warnings
+	if p.NewDomain != nil {
+		w = append(w, "spec.forProvider.newDomain is deprecated; change spec.forProvider.domain to rename the site")
+		errs = append(errs, validateDomain(forProviderPath.Child("newDomain"), *p.NewDomain)...)
+	}
+	if p.Timezone != nil {
+		errs = append(errs, validateTimezone(forProviderPath.Child("timezone"), *p.Timezone)...)
+	}
+	return w, errs
+}
+
+// validateTimezone checks that tz is the name of an IANA time zone.
+func validateTimezone(path *field.Path, tz string) field.ErrorList {
+	// LoadLocation also accepts "" and "Local", which Plausible does not.
+	if tz == "" || tz == "Local" {
+		return field.ErrorList{field.Invalid(path, tz, "must be an IANA time zone such as Europe/London")}
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return field.ErrorList{field.Invalid(path, tz, "must be an IANA time zone such as Europe/London")}
+	}
+	return nil
+}
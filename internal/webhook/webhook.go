@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook validates Plausible managed resources on admission, so that
+// specs the Plausible API would reject fail when they are applied rather than
+// when they are reconciled.
+//
+// The webhooks ignore failures to call them, so that resources can still be
+// applied while the provider is down. The checks that matter most are also
+// part of the CRD schemas, which the API server always enforces.
+package webhook
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	custompropertyv1beta1 "github.com/rossigee/provider-plausible/apis/customproperty/v1beta1"
+	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
+	guestv1beta1 "github.com/rossigee/provider-plausible/apis/guest/v1beta1"
+	sharedlinkv1beta1 "github.com/rossigee/provider-plausible/apis/sharedlink/v1beta1"
+	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-site-plausible-m-crossplane-io-v1beta1-site,mutating=false,failurePolicy=ignore,groups=site.plausible.m.crossplane.io,resources=sites,versions=v1beta1,name=sites.site.plausible.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goal-plausible-m-crossplane-io-v1beta1-goal,mutating=false,failurePolicy=ignore,groups=goal.plausible.m.crossplane.io,resources=goals,versions=v1beta1,name=goals.goal.plausible.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-guest-plausible-m-crossplane-io-v1beta1-guest,mutating=false,failurePolicy=ignore,groups=guest.plausible.m.crossplane.io,resources=guests,versions=v1beta1,name=guests.guest.plausible.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-sharedlink-plausible-m-crossplane-io-v1beta1-sharedlink,mutating=false,failurePolicy=ignore,groups=sharedlink.plausible.m.crossplane.io,resources=sharedlinks,versions=v1beta1,name=sharedlinks.sharedlink.plausible.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-customproperty-plausible-m-crossplane-io-v1beta1-customproperty,mutating=false,failurePolicy=ignore,groups=customproperty.plausible.m.crossplane.io,resources=customproperties,versions=v1beta1,name=customproperties.customproperty.plausible.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// Setup registers the validating webhooks of all Plausible managed resources
+// with the webhook server of the supplied manager.
+func Setup(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &sitev1beta1.Site{}).WithValidator(siteValidator).Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr, &goalv1beta1.Goal{}).WithValidator(goalValidator).Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr, &guestv1beta1.Guest{}).WithValidator(guestValidator).Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr, &sharedlinkv1beta1.SharedLink{}).WithValidator(sharedLinkValidator).Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr, &custompropertyv1beta1.CustomProperty{}).WithValidator(customPropertyValidator).Complete()
+}
+
+// A validator validates the parameters of a managed resource of kind T.
+type validator[T resource.Managed] struct {
+	kind schema.GroupKind
+
+	// forProvider returns the parameters of cr. An update that leaves them
+	// unchanged is not validated, so that the provider can still update
+	// resources that were created before a check was added.
+	forProvider func(cr T) any
+
+	// validate checks the parameters of cr, returning warnings for valid
+	// but discouraged settings.
+	validate func(cr T) (admission.Warnings, field.ErrorList)
+
+	// immutable checks that an update does not change parameters the
+	// Plausible API cannot change after creation. It may be nil.
+	immutable func(old, cr T) field.ErrorList
+}
+
+func (v validator[T]) ValidateCreate(_ context.Context, cr T) (admission.Warnings, error) {
+	w, errs := v.validate(cr)
+	return w, v.invalid(cr, errs)
+}
+
+func (v validator[T]) ValidateUpdate(_ context.Context, old, cr T) (admission.Warnings, error) {
+	// Never stand in the way of removing the finalizer of a deleted resource.
+	if meta.WasDeleted(cr) || equality.Semantic.DeepEqual(v.forProvider(old), v.forProvider(cr)) {
+		return nil, nil
+	}
+	w, errs := v.validate(cr)
+	if v.immutable != nil {
+		errs = append(errs, v.immutable(old, cr)...)
+	}
+	return w, v.invalid(cr, errs)
+}
+
+func (v validator[T]) ValidateDelete(context.Context, T) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v validator[T]) invalid(cr T, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(v.kind, cr.GetName(), errs)
+}
+
+// forProviderPath is the path of the parameters of every managed resource.
+var forProviderPath = field.NewPath("spec", "forProvider")
+
+// unchanged returns an error if the value of a field at path changed from a
+// set old value, including if it was cleared. Setting an unset field is
+// allowed, since the provider does so when it resolves references and late
+// initializes parameters.
+func unchanged[V comparable](path *field.Path, old, cr *V) field.ErrorList {
+	if old == nil || (cr != nil && *old == *cr) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path, value(cr), "field is immutable once set")}
+}
+
+func value[V any](v *V) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// validateDomain checks a site domain as Plausible does: a host name without
+// a scheme, optionally followed by a port and a path.
+func validateDomain(path *field.Path, domain string) field.ErrorList {
+	switch {
+	case domain == "":
+		return field.ErrorList{field.Required(path, "")}
+	case len(domain) > 255:
+		return field.ErrorList{field.TooLong(path, domain, 255)}
+	case strings.Contains(domain, "://"):
+		return field.ErrorList{field.Invalid(path, domain, "must not include a scheme such as https://")}
+	}
+
+	host, _, _ := strings.Cut(domain, "/")
+	host, port, hasPort := strings.Cut(host, ":")
+	for label := range strings.SplitSeq(host, ".") {
+		if !validLabel(label) {
+			return field.ErrorList{field.Invalid(path, domain, "must be a host name such as example.com")}
+		}
+	}
+	if hasPort && (port == "" || strings.IndexFunc(port, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0) {
+		return field.ErrorList{field.Invalid(path, domain, "port must be a number")}
+	}
+	if strings.IndexFunc(domain, unicode.IsSpace) >= 0 {
+		return field.ErrorList{field.Invalid(path, domain, "must not contain whitespace")}
+	}
+	return nil
+}
+
+// validLabel returns true if label is a DNS label of letters, digits and
+// inner hyphens. Letters outside ASCII are allowed for internationalized
+// domains.
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+	return strings.IndexFunc(label, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) < 0
+}
+
+// validateSiteDomain checks the siteDomain parameter of a resource that
+// belongs to a site, if it is set rather than resolved from a reference.
+func validateSiteDomain(domain *string) field.ErrorList {
+	if domain == nil {
+		return nil
+	}
+	return validateDomain(forProviderPath.Child("siteDomain"), *domain)
+}
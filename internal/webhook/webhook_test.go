@@ -0,0 +1,259 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	custompropertyv1beta1 "github.com/rossigee/provider-plausible/apis/customproperty/v1beta1"
+	goalv1beta1 "github.com/rossigee/provider-plausible/apis/goal/v1beta1"
+	guestv1beta1 "github.com/rossigee/provider-plausible/apis/guest/v1beta1"
+	sharedlinkv1beta1 "github.com/rossigee/provider-plausible/apis/sharedlink/v1beta1"
+	sitev1beta1 "github.com/rossigee/provider-plausible/apis/site/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateDomain(t *testing.T) {
+	cases := map[string]bool{
+		"example.com":           true,
+		"blog.example.co.uk":    true,
+		"localhost:8000":        true,
+		"example.com/shop":      true,
+		"bücher.example":        true,
+		"":                      false,
+		"https://example.com":   false,
+		"-example.com":          false,
+		"example..com":          false,
+		"example.com:http":      false,
+		"example.com/my page":   false,
+		"exa mple.com":          false,
+		"example.com:":          false,
+		"under_score.example":   false,
+		"example.com./trailing": false,
+	}
+
+	for domain, valid := range cases {
+		errs := validateDomain(field.NewPath("domain"), domain)
+		if got := len(errs) == 0; got != valid {
+			t.Errorf("validateDomain(%q): valid = %t, want %t (%v)", domain, got, valid, errs)
+		}
+	}
+}
+
+func site(domain, timezone string) *sitev1beta1.Site {
+	cr := &sitev1beta1.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "site"},
+		Spec:       sitev1beta1.SiteSpec{ForProvider: sitev1beta1.SiteParameters{Domain: domain}},
+	}
+	if timezone != "" {
+		cr.Spec.ForProvider.Timezone = &timezone
+	}
+	return cr
+}
+
+func TestSiteValidator(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := siteValidator.ValidateCreate(ctx, site("example.com", "Europe/London")); err != nil {
+		t.Errorf("ValidateCreate(valid): unexpected error: %v", err)
+	}
+	if _, err := siteValidator.ValidateCreate(ctx, site("example.com", "Mars/Olympus")); !kerrors.IsInvalid(err) {
+		t.Errorf("ValidateCreate(unknown timezone): got %v, want invalid", err)
+	}
+	if _, err := siteValidator.ValidateCreate(ctx, site("https://example.com", "")); !kerrors.IsInvalid(err) {
+		t.Errorf("ValidateCreate(scheme): got %v, want invalid", err)
+	}
+
+	old := site("example.com", "")
+	old.Spec.ForProvider.TeamID = ptr("team-a")
+
+	resolved := site("example.com", "")
+	resolved.Spec.ForProvider.TeamID = ptr("team-a")
+	if _, err := siteValidator.ValidateUpdate(ctx, site("example.com", ""), resolved); err != nil {
+		t.Errorf("ValidateUpdate(team set): unexpected error: %v", err)
+	}
+
+	moved := site("example.com", "")
+	moved.Spec.ForProvider.TeamID = ptr("team-b")
+	if _, err := siteValidator.ValidateUpdate(ctx, old, moved); !kerrors.IsInvalid(err) {
+		t.Errorf("ValidateUpdate(team changed): got %v, want invalid", err)
+	}
+	if _, err := siteValidator.ValidateUpdate(ctx, old, site("example.com", "")); !kerrors.IsInvalid(err) {
+		t.Errorf("ValidateUpdate(team cleared): got %v, want invalid", err)
+	}
+
+	// A resource that was created before a check existed can still be
+	// updated as long as its parameters do not change, and deleted.
+	legacy := site("https://example.com", "")
+	relabeled := legacy.DeepCopy()
+	relabeled.SetLabels(map[string]string{"team": "web"})
+	if _, err := siteValidator.ValidateUpdate(ctx, legacy, relabeled); err != nil {
+		t.Errorf("ValidateUpdate(unchanged parameters): unexpected error: %v", err)
+	}
+	deleted := moved.DeepCopy()
+	deleted.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	if _, err := siteValidator.ValidateUpdate(ctx, old, deleted); err != nil {
+		t.Errorf("ValidateUpdate(deleted): unexpected error: %v", err)
+	}
+}
+
+func goal(p goalv1beta1.GoalParameters) *goalv1beta1.Goal {
+	p.SiteDomain = ptr("example.com")
+	return &goalv1beta1.Goal{
+		ObjectMeta: metav1.ObjectMeta{Name: "goal"},
+		Spec:       goalv1beta1.GoalSpec{ForProvider: p},
+	}
+}
+
+func TestGoalValidator(t *testing.T) {
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		p     goalv1beta1.GoalParameters
+		valid bool
+	}{
+		"Event":            {p: goalv1beta1.GoalParameters{GoalType: "event", EventName: ptr("Signup")}, valid: true},
+		"EventNoName":      {p: goalv1beta1.GoalParameters{GoalType: "event"}},
+		"EventWithPage":    {p: goalv1beta1.GoalParameters{GoalType: "event", EventName: ptr("Signup"), PagePath: ptr("/")}},
+		"EventWithScroll":  {p: goalv1beta1.GoalParameters{GoalType: "event", EventName: ptr("Signup"), ScrollThreshold: intPtr(50)}},
+		"Page":             {p: goalv1beta1.GoalParameters{GoalType: "page", PagePath: ptr("/thanks"), ScrollThreshold: intPtr(50)}, valid: true},
+		"PageNoPath":       {p: goalv1beta1.GoalParameters{GoalType: "page"}},
+		"PageRelativePath": {p: goalv1beta1.GoalParameters{GoalType: "page", PagePath: ptr("thanks")}},
+		"PageWithCurrency": {p: goalv1beta1.GoalParameters{GoalType: "page", PagePath: ptr("/thanks"), Currency: ptr("EUR")}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := goalValidator.ValidateCreate(ctx, goal(tc.p))
+			if got := err == nil; got != tc.valid {
+				t.Errorf("ValidateCreate(...): valid = %t, want %t (%v)", got, tc.valid, err)
+			}
+		})
+	}
+
+	signup := goalv1beta1.GoalParameters{GoalType: "event", EventName: ptr("Signup")}
+	renamed := goalv1beta1.GoalParameters{GoalType: "event", EventName: ptr("Register")}
+
+	if _, err := goalValidator.ValidateUpdate(ctx, goal(signup), goal(renamed)); err != nil {
+		t.Errorf("ValidateUpdate(replaceable): unexpected error: %v", err)
+	}
+
+	signup.ReplacePolicy = goalv1beta1.ReplacePolicyNever
+	renamed.ReplacePolicy = goalv1beta1.ReplacePolicyNever
+	if _, err := goalValidator.ValidateUpdate(ctx, goal(signup), goal(renamed)); !kerrors.IsInvalid(err) {
+		t.Errorf("ValidateUpdate(replacePolicy Never): got %v, want invalid", err)
+	}
+}
+
+func TestGuestValidator(t *testing.T) {
+	cases := map[string]bool{
+		"jane@example.com":        true,
+		"jane":                    false,
+		"Jane <jane@example.com>": false,
+		"":                        false,
+	}
+	for email, valid := range cases {
+		cr := &guestv1beta1.Guest{Spec: guestv1beta1.GuestSpec{ForProvider: guestv1beta1.GuestParameters{Email: email}}}
+		_, err := guestValidator.ValidateCreate(context.Background(), cr)
+		if got := err == nil; got != valid {
+			t.Errorf("ValidateCreate(%q): valid = %t, want %t (%v)", email, got, valid, err)
+		}
+	}
+}
+
+func TestSharedLinkValidator(t *testing.T) {
+	generated := true
+	cases := map[string]struct {
+		p                sharedlinkv1beta1.SharedLinkParameters
+		connectionSecret bool
+		valid            bool
+		warnings         int
+	}{
+		"Unprotected": {
+			p:     sharedlinkv1beta1.SharedLinkParameters{Name: "client"},
+			valid: true,
+		},
+		"SecretRef": {
+			p: sharedlinkv1beta1.SharedLinkParameters{Name: "client", PasswordSecretRef: &xpv1.LocalSecretKeySelector{
+				LocalSecretReference: xpv1.LocalSecretReference{Name: "dashboard"},
+				Key:                  "password",
+			}},
+			valid: true,
+		},
+		"Generated": {
+			p:                sharedlinkv1beta1.SharedLinkParameters{Name: "client", GeneratePassword: &generated},
+			connectionSecret: true,
+			valid:            true,
+		},
+		"GeneratedWithoutConnectionSecret": {
+			p: sharedlinkv1beta1.SharedLinkParameters{Name: "client", GeneratePassword: &generated},
+		},
+		"InlinePassword": {
+			p:        sharedlinkv1beta1.SharedLinkParameters{Name: "client", Password: ptr("secret")},
+			valid:    true,
+			warnings: 1,
+		},
+		"NoName": {
+			p: sharedlinkv1beta1.SharedLinkParameters{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &sharedlinkv1beta1.SharedLink{Spec: sharedlinkv1beta1.SharedLinkSpec{ForProvider: tc.p}}
+			if tc.connectionSecret {
+				cr.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: "client-link"}
+			}
+
+			w, err := sharedLinkValidator.ValidateCreate(context.Background(), cr)
+			if got := err == nil; got != tc.valid {
+				t.Errorf("ValidateCreate(...): valid = %t, want %t (%v)", got, tc.valid, err)
+			}
+			if len(w) != tc.warnings {
+				t.Errorf("ValidateCreate(...): warnings %q, want %d", w, tc.warnings)
+			}
+		})
+	}
+}
+
+func TestCustomPropertyValidator(t *testing.T) {
+	cases := map[string]bool{
+		"plan":   true,
+		"":       false,
+		" plan":  false,
+		"plan\n": false,
+	}
+	for key, valid := range cases {
+		cr := &custompropertyv1beta1.CustomProperty{Spec: custompropertyv1beta1.CustomPropertySpec{ForProvider: custompropertyv1beta1.CustomPropertyParameters{Key: key}}}
+		_, err := customPropertyValidator.ValidateCreate(context.Background(), cr)
+		if got := err == nil; got != valid {
+			t.Errorf("ValidateCreate(%q): valid = %t, want %t (%v)", key, got, valid, err)
+		}
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}